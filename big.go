@@ -0,0 +1,92 @@
+package bitbytepack
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Read an arbitrary number of masked bits of array into a packed byte slice.
+// The bits are right-aligned and most significant byte first, so the slice
+// holds the big-endian representation of the embedded value.
+func ReadBytes(array []byte, mask []byte) ([]byte, error) {
+	if len(array) < len(mask) {
+		return []byte{}, ErrArrayShorterThanMask
+	}
+
+	n := CountOnes(mask)
+	output := make([]byte, (n+7)/8)
+
+	// Position in output of the next bit
+	pos := len(output)*8 - n
+
+	for i, m := range mask {
+		for bit := 7; bit >= 0; bit-- {
+			if m&(1<<uint(bit)) == 0 {
+				continue
+			}
+			if array[i]&(1<<uint(bit)) != 0 {
+				output[pos/8] |= 0x80 >> uint(pos%8)
+			}
+			pos++
+		}
+	}
+
+	return output, nil
+}
+
+// Write the bits of a big-endian byte slice to the masked bits of array. The
+// value must not have more significant bits than the mask.
+func WriteBytes(array []byte, mask []byte, value []byte) ([]byte, error) {
+	if len(array) < len(mask) {
+		return []byte{}, ErrArrayShorterThanMask
+	}
+
+	n := CountOnes(mask)
+	if bytesLen(value) > n {
+		return array, ErrNotEnoughBitsToEmbedValue
+	}
+
+	// Position in value of the next bit, negative while left of the value
+	pos := len(value)*8 - n
+
+	for i, m := range mask {
+		for bit := 7; bit >= 0; bit-- {
+			if m&(1<<uint(bit)) == 0 {
+				continue
+			}
+			if pos >= 0 && value[pos/8]&(0x80>>uint(pos%8)) != 0 {
+				array[i] |= 1 << uint(bit)
+			}
+			pos++
+		}
+	}
+
+	return array, nil
+}
+
+// Number of significant bits in a big-endian byte slice
+func bytesLen(value []byte) int {
+	for i, v := range value {
+		if v != 0 {
+			return (len(value)-i-1)*8 + bits.Len8(v)
+		}
+	}
+	return 0
+}
+
+// Read the masked bits of array as an unsigned big.Int
+func ReadBig(array []byte, mask []byte) (*big.Int, error) {
+	b, err := ReadBytes(array, mask)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// Write a non-negative big.Int to the masked bits of array
+func WriteBig(array []byte, mask []byte, value *big.Int) ([]byte, error) {
+	if value.Sign() < 0 {
+		return array, ErrNegativeValue
+	}
+	return WriteBytes(array, mask, value.Bytes())
+}
@@ -0,0 +1,83 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestReadBytes(t *testing.T) {
+	array := []byte{0x01, 0x02, 0x03}
+	mask := []byte{0x0F, 0x0F, 0x0F}
+	want := []byte{0x01, 0x23}
+
+	if got, e := ReadBytes(array, mask); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("ReadBytes(%x, %x) = %x, want %x", array, mask, got, want)
+	}
+
+	// 128-bit value spread over the low nibbles of 32 bytes
+	array = make([]byte, 32)
+	mask = bytes.Repeat([]byte{0x0F}, 32)
+	want = []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	for i, v := range want {
+		array[2*i] = 0xA0 | v>>4
+		array[2*i+1] = 0x50 | v&0x0F
+	}
+	if got, e := ReadBytes(array, mask); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("ReadBytes(%x, %x) = %x, want %x", array, mask, got, want)
+	}
+
+	if _, e := ReadBytes(array[:3], mask); e != ErrArrayShorterThanMask {
+		t.Errorf("ReadBytes(%x, %x) didn't throw '%s', but '%s'",
+			array[:3], mask, ErrArrayShorterThanMask, e)
+	}
+}
+
+func TestWriteBytes(t *testing.T) {
+	array := []byte{0xA0, 0xB0, 0xC0}
+	mask := []byte{0x0F, 0x0F, 0x0F}
+	value := []byte{0x01, 0x23}
+	want := []byte{0xA1, 0xB2, 0xC3}
+
+	if got, e := WriteBytes(array, mask, value); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteBytes(%x, %x, %x) = %x, want %x", array, mask, value, got, want)
+	}
+
+	// Leading zero bytes don't count towards the width
+	array = make([]byte, 3)
+	value = []byte{0x00, 0x00, 0x01, 0x23}
+	want = []byte{0x01, 0x02, 0x03}
+	if got, e := WriteBytes(array, mask, value); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteBytes(%x, %x, %x) = %x, want %x", array, mask, value, got, want)
+	}
+
+	array = make([]byte, 3)
+	value = []byte{0x12, 0x34}
+	if _, e := WriteBytes(array, mask, value); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteBytes(%x, %x, %x) didn't throw '%s', but '%s'",
+			array, mask, value, ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
+
+func TestReadWriteBig(t *testing.T) {
+	value, _ := new(big.Int).SetString("123e4567e89b12d3a456426614174000", 16)
+	mask := append([]byte{0x00, 0x00}, bytes.Repeat([]byte{0xF0}, 32)...)
+	array := make([]byte, len(mask))
+
+	array, e := WriteBig(array, mask, value)
+	if e != nil {
+		t.Fatalf("WriteBig(%x, %x, %x) threw '%s'", array, mask, value, e)
+	}
+	if got, e := ReadBig(array, mask); e != nil || got.Cmp(value) != 0 {
+		t.Errorf("ReadBig(%x, %x) = %x, want %x", array, mask, got, value)
+	}
+
+	if _, e := WriteBig(array, mask, new(big.Int).Lsh(big.NewInt(1), 128)); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteBig(%x, %x, 1<<128) didn't throw '%s', but '%s'",
+			array, mask, ErrNotEnoughBitsToEmbedValue, e)
+	}
+	if _, e := WriteBig(array, mask, big.NewInt(-1)); e != ErrNegativeValue {
+		t.Errorf("WriteBig(%x, %x, -1) didn't throw '%s', but '%s'",
+			array, mask, ErrNegativeValue, e)
+	}
+}
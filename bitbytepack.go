@@ -14,89 +14,179 @@ var (
 	ErrNotEnoughBitsToEmbedValue = errors.New("not enough values to embed value")
 	ErrArrayShorterThanMask      = errors.New("array is shorter than the mask")
 	ErrInterfaceTypeNotSupported = errors.New("deducted interface type is not supported")
+	ErrMaskTooWide               = errors.New("mask has more bits than fit in the value read")
+	ErrNegativeValue             = errors.New("negative value can't be embedded")
+	ErrMasksOverlap              = errors.New("masks overlap")
+	ErrMaskExceedsFrame          = errors.New("mask exceeds the frame")
+	ErrDuplicateFieldName        = errors.New("duplicate field name")
+	ErrUnknownField              = errors.New("unknown field")
+	ErrInvalidTag                = errors.New("invalid struct tag")
+	ErrEmptyMask                 = errors.New("mask has no bits set")
+	ErrInvalidMaskSpec           = errors.New("invalid mask spec")
+	ErrDestinationTooShort       = errors.New("destination is shorter than the number of masks")
+	ErrTooManyValues             = errors.New("more values to read than the limit")
+	ErrNotSingleBit              = errors.New("flag mask must have exactly one bit set")
+	ErrInvalidQFormat            = errors.New("mask width doesn't match the Q format")
+	ErrDuplicateEnumName         = errors.New("duplicate enum name")
+	ErrUnknownEnumCode           = errors.New("unknown enum code")
+	ErrUnknownEnumName           = errors.New("unknown enum name")
+	ErrInvalidTemplate           = errors.New("invalid command template")
 )
 
 // Constants
 const (
+	// Suggested limit for the Limit variants of the multi-read functions,
+	// such as MultReadFromArrayELimit, when decoding untrusted layouts
 	MaxNumberOfValuesToRead = 128
 )
 
-// Struct type to contain both a mask array and value
-type MaskValuePair struct {
-	Mask  []byte // mask array
-	Value uint   // value to be embedded
-}
+// Limit of the multi-read functions that don't take one
+const noLimit = -1
 
-// Struct type to contain both a mask array and value
-type MaskValuePair8 struct {
-	Mask  []byte // mask array
-	Value uint8  // value to be embedded
+// Number is the set of integer and float types that can be embedded in an array
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair16 struct {
-	Mask  []byte // mask array
-	Value uint16 // value to be embedded
+// Generic struct type to contain both a mask array and value
+type MaskValue[T Number] struct {
+	Mask   []byte // mask array
+	Value  T      // value to be embedded
+	Offset int    // index in the array of the first byte of the mask
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair32 struct {
-	Mask  []byte // mask array
-	Value uint32 // value to be embedded
+// Write the value to the array using the mask
+func (m MaskValue[T]) write(array []byte, opts writeOptions) ([]byte, error) {
+	sub, ok := at(array, m.Offset)
+	if !ok {
+		return array, ErrArrayShorterThanMask
+	}
+	if _, err := write(sub, m.Mask, m.Value, opts); err != nil {
+		return array, err
+	}
+	return array, nil
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair64 struct {
-	Mask  []byte // mask array
-	Value uint64 // value to be embedded
+// Offset and mask of the MaskValue
+func (m MaskValue[T]) placement() (int, []byte) {
+	return m.Offset, m.Mask
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePairS struct {
-	Mask  []byte // mask array
-	Value int    // value to be embedded
+// Value of the MaskValue
+func (m MaskValue[T]) value() interface{} {
+	return m.Value
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair8S struct {
-	Mask  []byte // mask array
-	Value int8   // value to be embedded
+// Interface implemented by every MaskValue, regardless of value type
+type maskValueWriter interface {
+	write(array []byte, opts writeOptions) ([]byte, error)
+	placement() (int, []byte)
+	value() interface{}
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair16S struct {
-	Mask  []byte // mask array
-	Value int16  // value to be embedded
-}
+// Type specific aliases of MaskValue
+type (
+	MaskValuePair    = MaskValue[uint]
+	MaskValuePair8   = MaskValue[uint8]
+	MaskValuePair16  = MaskValue[uint16]
+	MaskValuePair32  = MaskValue[uint32]
+	MaskValuePair64  = MaskValue[uint64]
+	MaskValuePairS   = MaskValue[int]
+	MaskValuePair8S  = MaskValue[int8]
+	MaskValuePair16S = MaskValue[int16]
+	MaskValuePair32S = MaskValue[int32]
+	MaskValuePair64S = MaskValue[int64]
+	MaskValuePair32F = MaskValue[float32]
+	MaskValuePair64F = MaskValue[float64]
+)
 
-// Struct type to contain both a mask array and value
-type MaskValuePair32S struct {
-	Mask  []byte // mask array
-	Value int32  // value to be embedded
-}
+// Order in which the masked bytes are combined into a value
+type BitOrder int
 
-// Struct type to contain both a mask array and value
-type MaskValuePair64S struct {
-	Mask  []byte // mask array
-	Value int64  // value to be embedded
-}
+const (
+	MSBFirst BitOrder = iota // first masked byte holds the most significant bits
+	LSBFirst                 // first masked byte holds the least significant bits
+)
 
-// Struct type to contain both a mask array and value
-type MaskValuePair32F struct {
-	Mask  []byte  // mask array
-	Value float32 // float32 value to be embedded
+// Index in a mask of length n of the k'th most significant byte
+func (o BitOrder) index(k int, n int) int {
+	if o == LSBFirst {
+		return n - k - 1
+	}
+	return k
 }
 
-// Struct type to contain both a mask array and value
-type MaskValuePair64F struct {
-	Mask  []byte  // mask array
-	Value float64 // float64 value to be embedded
+// Policy for values that don't fit in the bits of the mask
+type Overflow int
+
+const (
+	OverflowError    Overflow = iota // return ErrNotEnoughBitsToEmbedValue
+	OverflowTruncate                 // keep the lowest bits of the value, like a counter wrapping around
+	OverflowSaturate                 // write the nearest value that fits, signed values included
+)
+
+// Options for the write functions
+type writeOptions struct {
+	replace  bool     // clear the masked bits before writing
+	order    BitOrder // order of the masked bytes
+	all      bool     // try all the pairs of a multi-write, reporting every failure
+	overflow Overflow // what to do with values too wide for the mask
+	check    bool     // only check that the value can be written, leaving the array untouched
 }
 
 // Struct type to contain both a mask array and the value type to read
 type MaskTypePair struct {
-	Mask []byte       // mask array
-	Type reflect.Kind // type to read out
+	Mask   []byte       // mask array
+	Type   reflect.Kind // type to read out
+	Offset int          // index in the array of the first byte of the mask
+	Format Format       // encoding of the value in place of Type, or FormatKind
+}
+
+// Read the value of the pair, returning false if the type is not supported
+func (m MaskTypePair) read(array []byte) (interface{}, bool) {
+	switch m.Format {
+	case FormatKind:
+		return readKind(array, m.Mask, m.Type)
+	case FormatFloat16:
+		return ReadFromArray16F(array, m.Mask), true
+	case FormatBFloat16:
+		return ReadFromArray16BF(array, m.Mask), true
+	}
+	return nil, false
+}
+
+// Number of bits the mask must have to hold a value of the type of the pair
+func (m MaskTypePair) minWidth() int {
+	if m.Format == FormatFloat16 || m.Format == FormatBFloat16 {
+		return 16
+	}
+	switch m.Type {
+	case reflect.Float32:
+		return 32
+	case reflect.Float64:
+		return 64
+	}
+	return 0
+}
+
+// Create a MaskValue of the type of the pair from value, see maskValueOf
+func (m MaskTypePair) with(value interface{}) (maskValueWriter, error) {
+	switch m.Format {
+	case FormatKind:
+		return maskValueOf(m.Mask, m.Offset, m.Type, value)
+	case FormatFloat16, FormatBFloat16:
+		v, err := convertTo[float32](value)
+		if err != nil {
+			return nil, err
+		}
+		if m.Format == FormatFloat16 {
+			return MaskValuePair16F{m.Mask, v, m.Offset}, nil
+		}
+		return MaskValuePair16BF{m.Mask, v, m.Offset}, nil
+	}
+	return nil, ErrInterfaceTypeNotSupported
 }
 
 // Accumulative count ones in every byte of an []byte
@@ -108,22 +198,99 @@ func CountOnes(array []byte) int {
 	return count
 }
 
-// Base function for reading a value of an array
+// Sign-extend the lowest width bits of value
+func signExtend(value uint64, width int) int64 {
+	if width <= 0 || width >= 64 {
+		return int64(value)
+	}
+	shift := uint(64 - width)
+	return int64(value<<shift) >> shift
+}
+
+// Check that value can be represented as a two's complement number of width bits
+func fitsSigned(value int64, width int) bool {
+	if width >= 64 {
+		return true
+	}
+	if width == 0 {
+		return value == 0
+	}
+	limit := int64(1) << uint(width-1)
+	return value >= -limit && value < limit
+}
+
+// Nearest value to value that fits in a two's complement number of width bits
+func saturateSigned(value int64, width int) int64 {
+	if width == 0 {
+		return 0
+	}
+	limit := int64(1) << uint(width-1)
+	if value < 0 {
+		return -limit
+	}
+	return limit - 1
+}
+
+// Write value as a two's complement number spanning all the bits of the mask
+func writeSigned(array []byte, mask []byte, value int64, opts writeOptions) ([]byte, error) {
+	width := CountOnes(mask)
+	if !fitsSigned(value, width) {
+		switch opts.overflow {
+		case OverflowTruncate:
+			// The bits beyond the mask are dropped below
+		case OverflowSaturate:
+			value = saturateSigned(value, width)
+		default:
+			return array, ErrNotEnoughBitsToEmbedValue
+		}
+	}
+
+	// Keep only the bits of the field, so the sign is not extended beyond the mask
+	raw := uint64(value)
+	if width < 64 {
+		raw &= (1 << uint(width)) - 1
+	}
+	return writeToArray(array, mask, raw, opts)
+}
+
+// Base function for reading a value of an array. Masks with more set bits
+// than fit in an uint are truncated, use Read[uint64] or ReadFromArray64 to
+// read up to 64 bits on every platform. Returns 0 if the array is shorter
+// than the mask or the mask has more than 64 set bits.
 func ReadFromArray(array []byte, mask []byte) uint {
+	return uint(readFromArrayMSB(array, mask))
+}
+
+// Like ReadFromArray, but with the order of the masked bytes given by order
+func ReadFromArrayOrder(array []byte, mask []byte, order BitOrder) uint {
+	return uint(readFromArray(array, mask, order))
+}
+
+// Read the masked bits as a 64-bit value, regardless of the size of uint
+func readFromArray(array []byte, mask []byte, order BitOrder) uint64 {
+	if order == LSBFirst {
+		return readFromArrayLSB(array, mask)
+	}
+	return readFromArrayMSB(array, mask)
+}
+
+// Like readFromArray, but with the first masked byte holding the most
+// significant bits, the default that is kept small enough to be inlined
+func readFromArrayMSB(array []byte, mask []byte) uint64 {
 	if len(array) < len(mask) {
 		return 0
 	}
 
-	var finalValue uint = 0
+	var finalValue uint64 = 0
 	var b = 0
 
 	for i, m := range mask {
 
 		// Extract byte with mask
-		var maskedValue = uint(array[i] & m)
+		var maskedValue = uint64(array[i] & m)
 
 		// Shift all the way to the left
-		maskedValue <<= (bits.UintSize - 8) + bits.LeadingZeros8(m)
+		maskedValue <<= (64 - 8) + bits.LeadingZeros8(m)
 
 		// Shift to fit end of the right
 		maskedValue >>= b
@@ -135,26 +302,154 @@ func ReadFromArray(array []byte, mask []byte) uint {
 		b += bits.OnesCount8(m)
 	}
 
-	// Shift all the way to the left
-	finalValue >>= bits.UintSize - b
+	// Shift all the way to the right. With more than 64 bits in the mask the
+	// shift wraps around to more than 64, giving 0.
+	finalValue >>= uint(64 - b)
 
 	return finalValue
 }
 
-// Base function for writing an unsigned integer value
+// Like readFromArray, but with the first masked byte holding the least
+// significant bits
+func readFromArrayLSB(array []byte, mask []byte) uint64 {
+	if len(array) < len(mask) {
+		return 0
+	}
+
+	var finalValue uint64 = 0
+	var b = 0
+
+	for i := len(mask) - 1; i >= 0; i-- {
+		m := mask[i]
+		finalValue += (uint64(array[i]&m) << ((64 - 8) + bits.LeadingZeros8(m))) >> b
+		b += bits.OnesCount8(m)
+	}
+
+	return finalValue >> uint(64-b)
+}
+
+// Like ReadFromArray, but with the mask starting at index offset of the array
+func ReadFromArrayAt(array []byte, offset int, mask []byte) uint {
+	sub, ok := at(array, offset)
+	if !ok {
+		return 0
+	}
+	return ReadFromArray(sub, mask)
+}
+
+// Slice of array starting at offset, or false if offset is outside the array
+func at(array []byte, offset int) ([]byte, bool) {
+	if offset < 0 || offset > len(array) {
+		return nil, false
+	}
+	return array[offset:], true
+}
+
+// Like ReadFromArray, but returns ErrArrayShorterThanMask instead of 0 when
+// the array is shorter than the mask, and ErrMaskTooWide when the mask has
+// more set bits than fit in an uint
+func ReadFromArrayE(array []byte, mask []byte) (uint, error) {
+	if err := checkRead(array, mask); err != nil {
+		return 0, err
+	}
+	if CountOnes(mask) > bits.UintSize {
+		return 0, ErrMaskTooWide
+	}
+	return ReadFromArray(array, mask), nil
+}
+
+// Check that n values may be read with the given limit
+func checkCount(n int, limit int) error {
+	if limit != noLimit && n > limit {
+		return ErrTooManyValues
+	}
+	return nil
+}
+
+// Check that a value can be read of array using mask
+func checkRead(array []byte, mask []byte) error {
+	if len(array) < len(mask) {
+		return ErrArrayShorterThanMask
+	}
+	if CountOnes(mask) > 64 {
+		return ErrMaskTooWide
+	}
+	return nil
+}
+
+// Base function for writing an unsigned integer value. The value is ORed into
+// the array, so bits already set in the masked region are kept.
 func WriteToArray(array []byte, mask []byte, value uint) ([]byte, error) {
+	return writeToArray(array, mask, uint64(value), writeOptions{})
+}
+
+// Base function for replacing an unsigned integer value. The masked bits are
+// cleared before the value is written, so a template can be written repeatedly.
+func ReplaceInArray(array []byte, mask []byte, value uint) ([]byte, error) {
+	return writeToArray(array, mask, uint64(value), writeOptions{replace: true})
+}
+
+// Like WriteToArray, but with the order of the masked bytes given by order
+func WriteToArrayOrder(array []byte, mask []byte, value uint, order BitOrder) ([]byte, error) {
+	return writeToArray(array, mask, uint64(value), writeOptions{order: order})
+}
+
+// Like WriteToArray, but with values too wide for the mask handled as given
+// by overflow instead of returning ErrNotEnoughBitsToEmbedValue
+func WriteToArrayOverflow(array []byte, mask []byte, value uint, overflow Overflow) ([]byte, error) {
+	return writeToArray(array, mask, uint64(value), writeOptions{overflow: overflow})
+}
+
+// Like WriteToArray, but with the mask starting at index offset of the array
+func WriteToArrayAt(array []byte, offset int, mask []byte, value uint) ([]byte, error) {
+	sub, ok := at(array, offset)
+	if !ok {
+		return []byte{}, ErrArrayShorterThanMask
+	}
+	if _, err := WriteToArray(sub, mask, value); err != nil {
+		return array, err
+	}
+	return array, nil
+}
+
+// Like WriteToArray, but writes to a copy of the array, leaving the array
+// untouched. Useful for templates shared across goroutines.
+func WriteToCopy(array []byte, mask []byte, value uint) ([]byte, error) {
+	return WriteToArray(clone(array), mask, value)
+}
+
+// Copy array to a newly allocated slice
+func clone(array []byte) []byte {
+	c := make([]byte, len(array))
+	copy(c, array)
+	return c
+}
+
+// Write the lowest bits of a 64-bit value, regardless of the size of uint
+func writeToArray(array []byte, mask []byte, value uint64, opts writeOptions) ([]byte, error) {
 	if len(array) < len(mask) {
 		return []byte{}, ErrArrayShorterThanMask
 	}
 
-	if CountOnes(mask) < bits.Len(value) {
-		return array, ErrNotEnoughBitsToEmbedValue
+	if width := CountOnes(mask); width < bits.Len64(value) {
+		switch opts.overflow {
+		case OverflowTruncate:
+			value &= (1 << uint(width)) - 1
+		case OverflowSaturate:
+			value = (1 << uint(width)) - 1
+		default:
+			return array, ErrNotEnoughBitsToEmbedValue
+		}
+	}
+
+	if opts.check {
+		return array, nil
 	}
 
 	for i := range mask {
 
-		// Reverse iteration
-		j := len(mask) - i - 1
+		// Reverse iteration, from the least significant byte
+		j := opts.order.index(len(mask)-i-1, len(mask))
 
 		// Shift value if mask is shifted
 		valueByte := byte(value) << bits.TrailingZeros8(mask[j])
@@ -162,6 +457,11 @@ func WriteToArray(array []byte, mask []byte, value uint) ([]byte, error) {
 		// Apply mask
 		valueByte &= mask[j]
 
+		// Clear previous value
+		if opts.replace {
+			array[j] &^= mask[j]
+		}
+
 		// Add to array
 		array[j] |= valueByte
 
@@ -171,246 +471,534 @@ func WriteToArray(array []byte, mask []byte, value uint) ([]byte, error) {
 	return array, nil
 }
 
+// Kind of the type parameter, used to dispatch in the generic functions
+func kindOf[T Number]() reflect.Kind {
+	return reflect.TypeOf((*T)(nil)).Elem().Kind()
+}
+
+// Generic function for reading a value of an array. Signed types treat the
+// top bit of the masked field as the sign bit, float types read the IEEE 754
+// bit pattern.
+func Read[T Number](array []byte, mask []byte) T {
+	return read[T](array, mask, MSBFirst)
+}
+
+// Like Read, but with the order of the masked bytes given by order
+func ReadOrder[T Number](array []byte, mask []byte, order BitOrder) T {
+	return read[T](array, mask, order)
+}
+
+func read[T Number](array []byte, mask []byte, order BitOrder) T {
+	raw := readFromArray(array, mask, order)
+	switch kindOf[T]() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return T(signExtend(raw, CountOnes(mask)))
+	case reflect.Float32:
+		return T(math.Float32frombits(uint32(raw)))
+	case reflect.Float64:
+		return T(math.Float64frombits(raw))
+	default:
+		return T(raw)
+	}
+}
+
+// Like Read, but with the mask starting at index offset of the array
+func ReadAt[T Number](array []byte, offset int, mask []byte) T {
+	sub, ok := at(array, offset)
+	if !ok {
+		return 0
+	}
+	return Read[T](sub, mask)
+}
+
+// Like Write, but with the mask starting at index offset of the array
+func WriteAt[T Number](array []byte, offset int, mask []byte, value T) ([]byte, error) {
+	return MaskValue[T]{mask, value, offset}.write(array, writeOptions{})
+}
+
+// Like Read, but returns an error instead of 0 when the value can't be read,
+// see ReadFromArrayE
+func ReadE[T Number](array []byte, mask []byte) (T, error) {
+	if err := checkRead(array, mask); err != nil {
+		return 0, err
+	}
+	return Read[T](array, mask), nil
+}
+
+// Generic function for writing a value to an array. Signed types are written
+// as two's complement as wide as the mask, float types require the mask to
+// hold the full IEEE 754 bit pattern.
+func Write[T Number](array []byte, mask []byte, value T) ([]byte, error) {
+	return write(array, mask, value, writeOptions{})
+}
+
+// Generic function for replacing a value in an array, like Write but with
+// the masked bits cleared first
+func Replace[T Number](array []byte, mask []byte, value T) ([]byte, error) {
+	return write(array, mask, value, writeOptions{replace: true})
+}
+
+// Like Write, but with the order of the masked bytes given by order
+func WriteOrder[T Number](array []byte, mask []byte, value T, order BitOrder) ([]byte, error) {
+	return write(array, mask, value, writeOptions{order: order})
+}
+
+// Like Write, but with values too wide for the mask handled as given by
+// overflow. Signed types saturate to the most negative or most positive value
+// of the mask. Float types still require the full IEEE 754 bit pattern. This
+// is the overflow variant of the typed WriteToArray8, WriteToArray8S, ...
+// functions.
+func WriteOverflow[T Number](array []byte, mask []byte, value T, overflow Overflow) ([]byte, error) {
+	return write(array, mask, value, writeOptions{overflow: overflow})
+}
+
+// Generic function for writing a value to a copy of the array, leaving the
+// array untouched
+func WriteCopy[T Number](array []byte, mask []byte, value T) ([]byte, error) {
+	return write(clone(array), mask, value, writeOptions{})
+}
+
+func write[T Number](array []byte, mask []byte, value T, opts writeOptions) ([]byte, error) {
+	switch kindOf[T]() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return writeSigned(array, mask, int64(value), opts)
+	case reflect.Float32:
+		if CountOnes(mask) < 32 {
+			return array, ErrNotEnoughBitsToEmbedValue
+		}
+		return writeToArray(array, mask, uint64(math.Float32bits(float32(value))), opts)
+	case reflect.Float64:
+		if CountOnes(mask) < 64 {
+			return array, ErrNotEnoughBitsToEmbedValue
+		}
+		return writeToArray(array, mask, math.Float64bits(float64(value)), opts)
+	default:
+		return writeToArray(array, mask, uint64(value), opts)
+	}
+}
+
+// Generic function for reading multiple values from array using an array of masks
+func MultRead[T Number](array []byte, mask ...[]byte) []T {
+	output := make([]T, 0, len(mask))
+
+	for _, m := range mask {
+		output = append(output, Read[T](array, m))
+	}
+
+	return output
+}
+
+// Like MultRead, but returns an error if any of the values can't be read,
+// see ReadFromArrayE
+func MultReadE[T Number](array []byte, mask ...[]byte) ([]T, error) {
+	return multReadE[T](array, noLimit, mask)
+}
+
+// Like MultReadE, but returns ErrTooManyValues if there are more than limit
+// masks, for decoding layouts received from untrusted sources
+func MultReadELimit[T Number](array []byte, limit int, mask ...[]byte) ([]T, error) {
+	return multReadE[T](array, limit, mask)
+}
+
+func multReadE[T Number](array []byte, limit int, mask [][]byte) ([]T, error) {
+	if err := checkCount(len(mask), limit); err != nil {
+		return nil, err
+	}
+	output := make([]T, 0, len(mask))
+
+	for _, m := range mask {
+		v, err := ReadE[T](array, m)
+		if err != nil {
+			return output, err
+		}
+		output = append(output, v)
+	}
+
+	return output, nil
+}
+
 // Overload for uint8
 func ReadFromArray8(array []byte, mask []byte) uint8 {
-	return uint8(ReadFromArray(array, mask))
+	return Read[uint8](array, mask)
 }
 
 // Overload for uint16
 func ReadFromArray16(array []byte, mask []byte) uint16 {
-	return uint16(ReadFromArray(array, mask))
+	return Read[uint16](array, mask)
 }
 
 // Overload for uint32
 func ReadFromArray32(array []byte, mask []byte) uint32 {
-	return uint32(ReadFromArray(array, mask))
+	return Read[uint32](array, mask)
 }
 
 // Overload for uint64
 func ReadFromArray64(array []byte, mask []byte) uint64 {
-	return uint64(ReadFromArray(array, mask))
+	return Read[uint64](array, mask)
 }
 
-// Overload for int
+// Overload for int, treating the top bit of the masked field as the sign bit
 func ReadFromArrayS(array []byte, mask []byte) int {
-	return int(ReadFromArray(array, mask))
+	return Read[int](array, mask)
 }
-// Overload for uint8
+
+// Overload for int8, treating the top bit of the masked field as the sign bit
 func ReadFromArray8S(array []byte, mask []byte) int8 {
-	return int8(ReadFromArray(array, mask))
+	return Read[int8](array, mask)
 }
 
-// Overload for uint16
+// Overload for int16, treating the top bit of the masked field as the sign bit
 func ReadFromArray16S(array []byte, mask []byte) int16 {
-	return int16(ReadFromArray(array, mask))
+	return Read[int16](array, mask)
 }
-// Overload for uint32
+
+// Overload for int32, treating the top bit of the masked field as the sign bit
 func ReadFromArray32S(array []byte, mask []byte) int32 {
-	return int32(ReadFromArray(array, mask))
+	return Read[int32](array, mask)
 }
-// Overload for uint64
+
+// Overload for int64, treating the top bit of the masked field as the sign bit
 func ReadFromArray64S(array []byte, mask []byte) int64 {
-	return int64(ReadFromArray(array, mask))
+	return Read[int64](array, mask)
 }
 
 // Overload to read embedded float32 values of []byte array
 func ReadFromArray32F(array []byte, mask []byte) float32 {
-	return math.Float32frombits(ReadFromArray32(array, mask))
+	return Read[float32](array, mask)
 }
 
 // Overload to read embedded float64 values of []byte array
 func ReadFromArray64F(array []byte, mask []byte) float64 {
-	return math.Float64frombits(ReadFromArray64(array, mask))
+	return Read[float64](array, mask)
 }
 
+// Overload for int, written as a two's complement field as wide as the mask
 func WriteToArrayS(array []byte, mask []byte, value int) ([]byte, error) {
-	return WriteToArray(array, mask, uint(value))
+	return Write(array, mask, value)
 }
 func WriteToArray8S(array []byte, mask []byte, value int8) ([]byte, error) {
-	// Using double conversion to ensure correct representation
-	return WriteToArray(array, mask, uint(uint8(value)))
+	return Write(array, mask, value)
 }
 func WriteToArray16S(array []byte, mask []byte, value int16) ([]byte, error) {
-	// Using double conversion to ensure correct representation
-	return WriteToArray(array, mask, uint(uint16(value)))
+	return Write(array, mask, value)
 }
 func WriteToArray32S(array []byte, mask []byte, value int32) ([]byte, error) {
-	// Using double conversion to ensure correct representation
-	return WriteToArray(array, mask, uint(uint32(value)))
+	return Write(array, mask, value)
 }
 func WriteToArray64S(array []byte, mask []byte, value int64) ([]byte, error) {
-	// Using double conversion to ensure correct representation
-	return WriteToArray(array, mask, uint(uint64(value)))
+	return Write(array, mask, value)
 }
 
 func WriteToArray8(array []byte, mask []byte, value uint8) ([]byte, error) {
-	return WriteToArray(array, mask, uint(value))
+	return Write(array, mask, value)
 }
 func WriteToArray16(array []byte, mask []byte, value uint16) ([]byte, error) {
-	return WriteToArray(array, mask, uint(value))
+	return Write(array, mask, value)
 }
 func WriteToArray32(array []byte, mask []byte, value uint32) ([]byte, error) {
-	return WriteToArray(array, mask, uint(value))
+	return Write(array, mask, value)
 }
 func WriteToArray64(array []byte, mask []byte, value uint64) ([]byte, error) {
-	return WriteToArray(array, mask, uint(value))
+	return Write(array, mask, value)
 }
 
 // "Overload" to embed float32 value in a []byte array
 func WriteToArray32F(array []byte, mask []byte, value float32) ([]byte, error) {
-	if CountOnes(mask) < 32 {
-		return array, ErrNotEnoughBitsToEmbedValue
-	}
-	return WriteToArray32(array, mask, math.Float32bits(value))
+	return Write(array, mask, value)
 }
 
 // "Overload" to embed float64 value in a []byte array
 func WriteToArray64F(array []byte, mask []byte, value float64) ([]byte, error) {
-	if CountOnes(mask) < 64 {
-		return array, ErrNotEnoughBitsToEmbedValue
+	return Write(array, mask, value)
+}
+
+// Read a value of the given kind, returning false if the kind is not supported
+func readKind(array []byte, mask []byte, kind reflect.Kind) (interface{}, bool) {
+	switch kind {
+	case reflect.Uint:
+		return Read[uint](array, mask), true
+	case reflect.Uint8:
+		return Read[uint8](array, mask), true
+	case reflect.Uint16:
+		return Read[uint16](array, mask), true
+	case reflect.Uint32:
+		return Read[uint32](array, mask), true
+	case reflect.Uint64:
+		return Read[uint64](array, mask), true
+	case reflect.Int:
+		return Read[int](array, mask), true
+	case reflect.Int8:
+		return Read[int8](array, mask), true
+	case reflect.Int16:
+		return Read[int16](array, mask), true
+	case reflect.Int32:
+		return Read[int32](array, mask), true
+	case reflect.Int64:
+		return Read[int64](array, mask), true
+	case reflect.Float32:
+		return Read[float32](array, mask), true
+	case reflect.Float64:
+		return Read[float64](array, mask), true
+	case reflect.Uintptr:
+		return Read[uintptr](array, mask), true
+	case reflect.Bool:
+		return isSet(array, mask), true
 	}
-	return WriteToArray64(array, mask, math.Float64bits(value))
+	return nil, false
 }
 
-// Read multiple values from array using an array of masks
-func MultReadFromArray(array []byte, mask ...MaskTypePair) []interface{} {
-	output := make([]interface{}, 0, MaxNumberOfValuesToRead)
-
-	for _, m := range mask {
-		switch m.Type {
-		case reflect.Uint:
-			output = append(output, ReadFromArray(array, m.Mask))
-		case reflect.Uint8:
-			output = append(output, ReadFromArray8(array, m.Mask))
-		case reflect.Uint16:
-			output = append(output, ReadFromArray16(array, m.Mask))
-		case reflect.Uint32:
-			output = append(output, ReadFromArray32(array, m.Mask))
-		case reflect.Uint64:
-			output = append(output, ReadFromArray64(array, m.Mask))
-		case reflect.Int:
-			output = append(output, ReadFromArrayS(array, m.Mask))
-		case reflect.Int8:
-			output = append(output, ReadFromArray8S(array, m.Mask))
-		case reflect.Int16:
-			output = append(output, ReadFromArray16S(array, m.Mask))
-		case reflect.Int32:
-			output = append(output, ReadFromArray32S(array, m.Mask))
-		case reflect.Int64:
-			output = append(output, ReadFromArray64S(array, m.Mask))
-		case reflect.Float32:
-			output = append(output, ReadFromArray32F(array, m.Mask))
-		case reflect.Float64:
-			output = append(output, ReadFromArray64F(array, m.Mask))
+// Check if any of the masked bits are set
+func isSet(array []byte, mask []byte) bool {
+	if len(array) < len(mask) {
+		return false
+	}
+	for i, m := range mask {
+		if array[i]&m != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Create a MaskValue of the given kind from value, returning
+// ErrInterfaceTypeNotSupported if value is not a number or kind is not
+// supported, and ErrNotEnoughBitsToEmbedValue if value is out of range
+func maskValueOf(mask []byte, offset int, kind reflect.Kind, value interface{}) (maskValueWriter, error) {
+	switch kind {
+	case reflect.Uint:
+		return newMaskValue[uint](mask, offset, value)
+	case reflect.Uint8:
+		return newMaskValue[uint8](mask, offset, value)
+	case reflect.Uint16:
+		return newMaskValue[uint16](mask, offset, value)
+	case reflect.Uint32:
+		return newMaskValue[uint32](mask, offset, value)
+	case reflect.Uint64:
+		return newMaskValue[uint64](mask, offset, value)
+	case reflect.Int:
+		return newMaskValue[int](mask, offset, value)
+	case reflect.Int8:
+		return newMaskValue[int8](mask, offset, value)
+	case reflect.Int16:
+		return newMaskValue[int16](mask, offset, value)
+	case reflect.Int32:
+		return newMaskValue[int32](mask, offset, value)
+	case reflect.Int64:
+		return newMaskValue[int64](mask, offset, value)
+	case reflect.Float32:
+		return newMaskValue[float32](mask, offset, value)
+	case reflect.Float64:
+		return newMaskValue[float64](mask, offset, value)
+	case reflect.Uintptr:
+		return newMaskValue[uintptr](mask, offset, value)
+	case reflect.Bool:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Bool {
+			return nil, ErrInterfaceTypeNotSupported
 		}
+		return MaskValuePairBool{mask, rv.Bool(), offset}, nil
 	}
+	return nil, ErrInterfaceTypeNotSupported
+}
 
-	return output
+func newMaskValue[T Number](mask []byte, offset int, value interface{}) (maskValueWriter, error) {
+	v, err := convertTo[T](value)
+	if err != nil {
+		return nil, err
+	}
+	return MaskValue[T]{mask, v, offset}, nil
 }
 
-// Read multiple values (uint8) from array using an array of masks
-func MultReadFromArray8(array []byte, mask ...[]byte) []uint8 {
-	output := make([]uint8, 0, MaxNumberOfValuesToRead)
+// Convert a number of any type to T. Integers must be representable in T.
+func convertTo[T Number](value interface{}) (T, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
 
-	for _, m := range mask {
-		output = append(output, ReadFromArray8(array, m))
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || !isNumberKind(rv.Kind()) {
+		return 0, ErrInterfaceTypeNotSupported
 	}
 
-	return output
+	t := reflect.TypeOf(T(0))
+	c := rv.Convert(t)
+
+	// Converting back must give the same value and sign, unless converting to float
+	if !isFloatKind(t.Kind()) &&
+		(c.Convert(rv.Type()).Interface() != rv.Interface() || isNegative(c) != isNegative(rv)) {
+		return 0, ErrNotEnoughBitsToEmbedValue
+	}
+	return c.Interface().(T), nil
 }
 
-// Read multiple values (uint16) from array using an array of masks
-func MultReadFromArray16(array []byte, mask ...[]byte) []uint16 {
-	output := make([]uint16, 0, MaxNumberOfValuesToRead)
+// Check if kind is one of the kinds in Number
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
 
-	for _, m := range mask {
-		output = append(output, ReadFromArray16(array, m))
-	}
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
 
-	return output
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
 }
 
-// Read multiple values (uint32) from array using an array of masks
-func MultReadFromArray32(array []byte, mask ...[]byte) []uint32 {
-	output := make([]uint32, 0, MaxNumberOfValuesToRead)
+// Read multiple values from array using an array of masks. Masks of kinds
+// that are not supported are skipped, see MultReadFromArrayE.
+func MultReadFromArray(array []byte, mask ...MaskTypePair) []interface{} {
+	output := make([]interface{}, 0, len(mask))
 
 	for _, m := range mask {
-		output = append(output, ReadFromArray32(array, m))
+		sub, _ := at(array, m.Offset)
+		if v, ok := m.read(sub); ok {
+			output = append(output, v)
+		}
 	}
 
 	return output
 }
 
-// Read multiple values (uint64) from array using an array of masks
-func MultReadFromArray64(array []byte, mask ...[]byte) []uint64 {
-	output := make([]uint64, 0, MaxNumberOfValuesToRead)
+// Like MultReadFromArray, but returns an error if any of the values can't be
+// read, see ReadFromArrayE. Masks of kinds that are not supported give a
+// *MaskError wrapping ErrInterfaceTypeNotSupported, with the index of the mask.
+func MultReadFromArrayE(array []byte, mask ...MaskTypePair) ([]interface{}, error) {
+	return multReadFromArrayE(array, noLimit, mask)
+}
 
-	for _, m := range mask {
-		output = append(output, ReadFromArray64(array, m))
+// Like MultReadFromArrayE, but returns ErrTooManyValues if there are more
+// than limit masks, for decoding layouts received from untrusted sources
+func MultReadFromArrayELimit(array []byte, limit int, mask ...MaskTypePair) ([]interface{}, error) {
+	return multReadFromArrayE(array, limit, mask)
+}
+
+func multReadFromArrayE(array []byte, limit int, mask []MaskTypePair) ([]interface{}, error) {
+	if err := checkCount(len(mask), limit); err != nil {
+		return nil, err
 	}
+	output := make([]interface{}, 0, len(mask))
 
-	return output
+	for i, m := range mask {
+		sub, ok := at(array, m.Offset)
+		if !ok {
+			return output, ErrArrayShorterThanMask
+		}
+		if err := checkRead(sub, m.Mask); err != nil {
+			return output, err
+		}
+		v, ok := m.read(sub)
+		if !ok {
+			return output, &MaskError{i, -1, ErrInterfaceTypeNotSupported}
+		}
+		output = append(output, v)
+	}
+
+	return output, nil
 }
 
-// Read multiple float32 values from array using an array of masks
-func MultReadFromArray32F(array []byte, mask ...[]byte) []float32 {
-	output := make([]float32, 0, MaxNumberOfValuesToRead)
+// Read multiple values (uint8) from array using an array of masks
+func MultReadFromArray8(array []byte, mask ...[]byte) []uint8 {
+	return MultRead[uint8](array, mask...)
+}
 
-	for _, m := range mask {
-		output = append(output, ReadFromArray32F(array, m))
-	}
+// Read multiple values (uint16) from array using an array of masks
+func MultReadFromArray16(array []byte, mask ...[]byte) []uint16 {
+	return MultRead[uint16](array, mask...)
+}
 
-	return output
+// Read multiple values (uint32) from array using an array of masks
+func MultReadFromArray32(array []byte, mask ...[]byte) []uint32 {
+	return MultRead[uint32](array, mask...)
+}
+
+// Read multiple values (uint64) from array using an array of masks
+func MultReadFromArray64(array []byte, mask ...[]byte) []uint64 {
+	return MultRead[uint64](array, mask...)
+}
+
+// Read multiple float32 values from array using an array of masks
+func MultReadFromArray32F(array []byte, mask ...[]byte) []float32 {
+	return MultRead[float32](array, mask...)
 }
 
 // Read multiple float64 values from array using an array of masks
 func MultReadFromArray64F(array []byte, mask ...[]byte) []float64 {
-	output := make([]float64, 0, MaxNumberOfValuesToRead)
+	return MultRead[float64](array, mask...)
+}
 
-	for _, m := range mask {
-		output = append(output, ReadFromArray64F(array, m))
-	}
+// Write multiple values to array using MaskValue pairs of any value type. If
+// any of the values can't be written, the array is left as it was and the
+// first failing pair is returned as a *FieldError.
+func MultWriteToArray(array []byte, mvp ...interface{}) ([]byte, error) {
+	return multWrite(array, writeOptions{}, mvp)
+}
 
-	return output
+// Replace multiple values in array using MaskValue pairs of any value type.
+// If any of the values can't be written, the array is left as it was.
+func MultReplaceInArray(array []byte, mvp ...interface{}) ([]byte, error) {
+	return multWrite(array, writeOptions{replace: true}, mvp)
 }
 
-func MultWriteToArray(array []byte, mvp ...interface{}) ([]byte, error) {
-	var err error = nil
-	// Iterate over all Mask-Value pairs
-	for _, m := range mvp {
-		switch m.(type) {
-		case MaskValuePair:
-			array, err = WriteToArray(array, m.(MaskValuePair).Mask, m.(MaskValuePair).Value)
-		case MaskValuePair8:
-			array, err = WriteToArray8(array, m.(MaskValuePair8).Mask, m.(MaskValuePair8).Value)
-		case MaskValuePair16:
-			array, err = WriteToArray16(array, m.(MaskValuePair16).Mask, m.(MaskValuePair16).Value)
-		case MaskValuePair32:
-			array, err = WriteToArray32(array, m.(MaskValuePair32).Mask, m.(MaskValuePair32).Value)
-		case MaskValuePair64:
-			array, err = WriteToArray64(array, m.(MaskValuePair64).Mask, m.(MaskValuePair64).Value)
-		case MaskValuePairS:
-			array, err = WriteToArrayS(array, m.(MaskValuePairS).Mask, m.(MaskValuePairS).Value)
-		case MaskValuePair8S:
-			array, err = WriteToArray8S(array, m.(MaskValuePair8S).Mask, m.(MaskValuePair8S).Value)
-		case MaskValuePair16S:
-			array, err = WriteToArray16S(array, m.(MaskValuePair16S).Mask, m.(MaskValuePair16S).Value)
-		case MaskValuePair32S:
-			array, err = WriteToArray32S(array, m.(MaskValuePair32S).Mask, m.(MaskValuePair32S).Value)
-		case MaskValuePair64S:
-			array, err = WriteToArray64S(array, m.(MaskValuePair64S).Mask, m.(MaskValuePair64S).Value)
-		case MaskValuePair32F:
-			array, err = WriteToArray32F(array, m.(MaskValuePair32F).Mask, m.(MaskValuePair32F).Value)
-		case MaskValuePair64F:
-			array, err = WriteToArray64F(array, m.(MaskValuePair64F).Mask, m.(MaskValuePair64F).Value)
-		default:
-			return array, ErrInterfaceTypeNotSupported
+// Like MultWriteToArray, but tries all the pairs instead of stopping at the
+// first that can't be written. Every failing pair is returned as a *FieldError,
+// joined into one error.
+func MultWriteToArrayAll(array []byte, mvp ...interface{}) ([]byte, error) {
+	return multWrite(array, writeOptions{all: true}, mvp)
+}
+
+// Like MultReplaceInArray, but reports every failing pair, see
+// MultWriteToArrayAll
+func MultReplaceInArrayAll(array []byte, mvp ...interface{}) ([]byte, error) {
+	return multWrite(array, writeOptions{replace: true, all: true}, mvp)
+}
+
+// Like MultWriteToArray, but with values too wide for their mask handled as
+// given by overflow, see WriteOverflow
+func MultWriteToArrayOverflow(array []byte, overflow Overflow, mvp ...interface{}) ([]byte, error) {
+	return multWrite(array, writeOptions{overflow: overflow}, mvp)
+}
+
+// Write multiple values to a copy of the array, leaving the array untouched
+func MultWriteToCopy(array []byte, mvp ...interface{}) ([]byte, error) {
+	return multWrite(clone(array), writeOptions{}, mvp)
+}
+
+func multWrite(array []byte, opts writeOptions, mvp []interface{}) ([]byte, error) {
+	var errs []error
+
+	// Check all the Mask-Value pairs before writing any, so the array is left
+	// as it was if one of them fails
+	check := opts
+	check.check = true
+	for i, m := range mvp {
+		w, ok := m.(maskValueWriter)
+		if !ok {
+			errs = append(errs, &FieldError{Index: i, Value: m, Err: ErrInterfaceTypeNotSupported})
+		} else if _, err := w.write(array, check); err != nil {
+			_, mask := w.placement()
+			errs = append(errs, &FieldError{Index: i, Mask: mask, Value: w.value(), Err: err})
 		}
+		if errs != nil && !opts.all {
+			break
+		}
+	}
 
-		if err != nil {
-			return array, err
+	if errs != nil {
+		if len(errs) == 1 {
+			return array, errs[0]
 		}
+		return array, errors.Join(errs...)
 	}
 
-	return array, err
+	// Iterate over all Mask-Value pairs
+	for _, m := range mvp {
+		m.(maskValueWriter).write(array, opts)
+	}
+	return array, nil
 }
@@ -2,6 +2,8 @@ package bitbytepack
 
 import (
 	"bytes"
+	"errors"
+	"math/bits"
 	"reflect"
 	"testing"
 )
@@ -37,6 +39,255 @@ func TestReadFromArray(t *testing.T) {
 	}
 }
 
+func TestMultReadFromArrayKinds(t *testing.T) {
+	array := []byte{0x12, 0x34}
+	masks := []MaskTypePair{
+		{[]byte{0x02}, reflect.Bool, 0, 0},
+		{[]byte{0x80}, reflect.Bool, 0, 0},
+		{[]byte{0xFF, 0xFF}, reflect.Uintptr, 0, 0}}
+	want := []interface{}{true, false, uintptr(0x1234)}
+
+	if got, e := MultReadFromArrayE(array, masks...); e != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("MultReadFromArrayE(%x, %x) = %v, '%v', want %v", array, masks, got, e, want)
+	}
+
+	// Unsupported kinds are skipped, or reported with their index
+	masks = append(masks[:1], MaskTypePair{[]byte{0xFF}, reflect.String, 0, 0}, masks[2])
+	want = []interface{}{true, uintptr(0x1234)}
+	if got := MultReadFromArray(array, masks...); !reflect.DeepEqual(got, want) {
+		t.Errorf("MultReadFromArray(%x, %x) = %v, want %v", array, masks, got, want)
+	}
+
+	var me *MaskError
+	if _, e := MultReadFromArrayE(array, masks...); !errors.As(e, &me) || me.Index != 1 || !errors.Is(e, ErrInterfaceTypeNotSupported) {
+		t.Errorf("MultReadFromArrayE(%x, %x) didn't report mask 1 as '%s', but '%v'",
+			array, masks, ErrInterfaceTypeNotSupported, e)
+	}
+}
+
+func TestMaxNumberOfValuesToRead(t *testing.T) {
+	array := []byte{0x12, 0x34, 0x56}
+	masks := [][]byte{{0xFF}, {0x00, 0xFF}, {0x00, 0x00, 0xFF}}
+	pairs := []MaskTypePair{
+		{masks[0], reflect.Uint8, 0, 0},
+		{masks[1], reflect.Uint8, 0, 0},
+		{masks[2], reflect.Uint8, 0, 0}}
+
+	// Output is sized from the number of masks
+	if got := MultRead[uint8](array, masks...); len(got) != 3 || cap(got) != 3 {
+		t.Errorf("MultRead[uint8](%x, %x) has len %d and cap %d, want 3", array, masks, len(got), cap(got))
+	}
+	if got := MultReadFromArray(array, pairs[:1]...); cap(got) != 1 {
+		t.Errorf("MultReadFromArray(%x, %x) has cap %d, want 1", array, pairs[:1], cap(got))
+	}
+
+	if _, e := MultReadELimit[uint8](array, 2, masks...); e != ErrTooManyValues {
+		t.Errorf("MultReadELimit[uint8](%x, 2, %x) didn't throw '%s', but '%s'", array, masks, ErrTooManyValues, e)
+	}
+	if _, e := MultReadFromArrayELimit(array, 2, pairs...); e != ErrTooManyValues {
+		t.Errorf("MultReadFromArrayELimit(%x, 2, %x) didn't throw '%s', but '%s'", array, pairs, ErrTooManyValues, e)
+	}
+	if _, e := MultReadFromArrayCheckedLimit(array, 2, pairs...); e != ErrTooManyValues {
+		t.Errorf("MultReadFromArrayCheckedLimit(%x, 2, %x) didn't throw '%s', but '%s'", array, pairs, ErrTooManyValues, e)
+	}
+	if e := MultReadIntoLimit(array, make([]uint8, 3), 2, masks...); e != ErrTooManyValues {
+		t.Errorf("MultReadIntoLimit(%x, dst, 2, %x) didn't throw '%s', but '%s'", array, masks, ErrTooManyValues, e)
+	}
+	if e := MultReadFromArrayIntoLimit(array, make([]interface{}, 3), 2, masks...); e != ErrTooManyValues {
+		t.Errorf("MultReadFromArrayIntoLimit(%x, dst, 2, %x) didn't throw '%s', but '%s'", array, masks, ErrTooManyValues, e)
+	}
+	if got, e := MultReadELimit[uint8](array, 2, masks[:2]...); e != nil || len(got) != 2 {
+		t.Errorf("MultReadELimit[uint8](%x, 2, %x) = %x, '%v'", array, masks[:2], got, e)
+	}
+	if got, e := MultReadE[uint8](array, masks...); e != nil || len(got) != 3 {
+		t.Errorf("MultReadE[uint8](%x, %x) = %x, '%v'", array, masks, got, e)
+	}
+
+	// Functions without errors are not limited
+	if got := MultRead[uint8](array, masks...); len(got) != 3 {
+		t.Errorf("MultRead[uint8](%x, %x) = %x, want 3 values", array, masks, got)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	array := make([]byte, 48)
+	array[40], array[41] = 0x01, 0x02
+	mask := []byte{0x0F, 0x0F}
+	want := uint(0x12)
+
+	if got := ReadFromArrayAt(array, 40, mask); got != want {
+		t.Errorf("ReadFromArrayAt(%x, 40, %x) = %x, want %x", array, mask, got, want)
+	}
+	if got := ReadAt[uint8](array, 40, mask); got != uint8(want) {
+		t.Errorf("ReadAt[uint8](%x, 40, %x) = %x, want %x", array, mask, got, want)
+	}
+	if got := ReadFromArrayAt(array, 47, mask); got != 0 {
+		t.Errorf("ReadFromArrayAt(%x, 47, %x) = %x, want 0", array, mask, got)
+	}
+	if got := ReadFromArrayAt(array, 49, mask); got != 0 {
+		t.Errorf("ReadFromArrayAt(%x, 49, %x) = %x, want 0", array, mask, got)
+	}
+
+	masks := []MaskTypePair{
+		{mask, reflect.Uint8, 40, 0},
+		{[]byte{0x0F}, reflect.Uint8, 41, 0}}
+	wantValues := []interface{}{uint8(0x12), uint8(0x02)}
+	if got := MultReadFromArray(array, masks...); !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("MultReadFromArray(%x, %x) = %x, want %x", array, masks, got, wantValues)
+	}
+	masks = append(masks, MaskTypePair{mask, reflect.Uint8, 49, 0})
+	if _, e := MultReadFromArrayE(array, masks...); e != ErrArrayShorterThanMask {
+		t.Errorf("MultReadFromArrayE(%x, %x) didn't throw '%s', but '%s'",
+			array, masks, ErrArrayShorterThanMask, e)
+	}
+
+	buffer := make([]byte, 4)
+	wantArray := []byte{0x00, 0x00, 0x01, 0x02}
+	if got, e := WriteToArrayAt(buffer, 2, mask, want); e != nil || !bytes.Equal(got, wantArray) {
+		t.Errorf("WriteToArrayAt(%x, 2, %x, %x) = %x, want %x", buffer, mask, want, got, wantArray)
+	}
+	buffer = make([]byte, 4)
+	if got, e := WriteAt(buffer, 2, mask, int8(want)); e != nil || !bytes.Equal(got, wantArray) {
+		t.Errorf("WriteAt(%x, 2, %x, %x) = %x, want %x", buffer, mask, want, got, wantArray)
+	}
+	if _, e := WriteToArrayAt(buffer, 3, mask, want); e != ErrArrayShorterThanMask {
+		t.Errorf("WriteToArrayAt(%x, 3, %x, %x) didn't throw '%s', but '%s'",
+			buffer, mask, want, ErrArrayShorterThanMask, e)
+	}
+
+	buffer = make([]byte, 4)
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0xF0, 0xF0}, 0x12, 2},
+		MaskValuePair8{[]byte{0x0F}, 0x3, 1},
+	}
+	wantArray = []byte{0x00, 0x03, 0x10, 0x20}
+	if got, e := MultWriteToArray(buffer, maskValuePairs...); e != nil || !bytes.Equal(got, wantArray) {
+		t.Errorf("MultWriteToArray(%x, %x) = %x, want %x", buffer, maskValuePairs, got, wantArray)
+	}
+}
+
+func TestReadFromArrayOrder(t *testing.T) {
+	tests := []struct {
+		array   []byte
+		mask    []byte
+		wantMSB uint
+		wantLSB uint
+	}{
+		{[]byte{0x01, 0x02}, []byte{0x0F, 0x0F}, 0x12, 0x21},
+		{[]byte{0x10, 0x02}, []byte{0xF0, 0x0F}, 0x12, 0x21},
+		{[]byte{0x04, 0x02}, []byte{0x3C, 0x0F}, 0x12, 0x21},
+		{[]byte{0x34, 0x12}, []byte{0xFF, 0xFF}, 0x3412, 0x1234},
+		// Test early return
+		{[]byte{}, []byte{0x0F}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := ReadFromArrayOrder(tt.array, tt.mask, MSBFirst); got != tt.wantMSB {
+			t.Errorf("ReadFromArrayOrder(%x, %x, MSBFirst) = %x, want %x", tt.array, tt.mask, got, tt.wantMSB)
+		}
+		if got := ReadFromArrayOrder(tt.array, tt.mask, LSBFirst); got != tt.wantLSB {
+			t.Errorf("ReadFromArrayOrder(%x, %x, LSBFirst) = %x, want %x", tt.array, tt.mask, got, tt.wantLSB)
+		}
+		if got := ReadOrder[uint16](tt.array, tt.mask, LSBFirst); got != uint16(tt.wantLSB) {
+			t.Errorf("ReadOrder[uint16](%x, %x, LSBFirst) = %x, want %x", tt.array, tt.mask, got, tt.wantLSB)
+		}
+	}
+
+	// Sign bit is in the last masked byte
+	array := []byte{0xF6, 0xFF}
+	mask := []byte{0xFF, 0xFF}
+	if got := ReadOrder[int16](array, mask, LSBFirst); got != -10 {
+		t.Errorf("ReadOrder[int16](%x, %x, LSBFirst) = %d, want %d", array, mask, got, -10)
+	}
+}
+
+func TestWriteToArrayOrder(t *testing.T) {
+	tests := []struct {
+		array   []byte
+		mask    []byte
+		value   uint
+		wantMSB []byte
+		wantLSB []byte
+	}{
+		{[]byte{0x00, 0x00}, []byte{0x0F, 0x0F}, 0x12, []byte{0x01, 0x02}, []byte{0x02, 0x01}},
+		{[]byte{0x0a, 0x00}, []byte{0xF0, 0x0F}, 0x12, []byte{0x1a, 0x02}, []byte{0x2a, 0x01}},
+		{[]byte{0x00, 0x00}, []byte{0xFF, 0xFF}, 0x1234, []byte{0x12, 0x34}, []byte{0x34, 0x12}},
+	}
+
+	for _, tt := range tests {
+		array := clone(tt.array)
+		if got, e := WriteToArrayOrder(array, tt.mask, tt.value, MSBFirst); e != nil || !bytes.Equal(got, tt.wantMSB) {
+			t.Errorf("WriteToArrayOrder(%x, %x, %x, MSBFirst) = %x, want %x", tt.array, tt.mask, tt.value, got, tt.wantMSB)
+		}
+		array = clone(tt.array)
+		if got, e := WriteToArrayOrder(array, tt.mask, tt.value, LSBFirst); e != nil || !bytes.Equal(got, tt.wantLSB) {
+			t.Errorf("WriteToArrayOrder(%x, %x, %x, LSBFirst) = %x, want %x", tt.array, tt.mask, tt.value, got, tt.wantLSB)
+		}
+		array = clone(tt.array)
+		if got, e := WriteOrder(array, tt.mask, uint16(tt.value), LSBFirst); e != nil || !bytes.Equal(got, tt.wantLSB) {
+			t.Errorf("WriteOrder(%x, %x, %x, LSBFirst) = %x, want %x", tt.array, tt.mask, tt.value, got, tt.wantLSB)
+		}
+	}
+
+	// Test early return
+	if _, e := WriteToArrayOrder([]byte{0x00}, []byte{0x0F, 0x0F}, 0x12, LSBFirst); e != ErrArrayShorterThanMask {
+		t.Errorf("WriteToArrayOrder didn't throw '%s', but '%s'", ErrArrayShorterThanMask, e)
+	}
+	if _, e := WriteToArrayOrder([]byte{0x00, 0x00}, []byte{0x00, 0x0F}, 0x12, LSBFirst); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteToArrayOrder didn't throw '%s', but '%s'", ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
+
+func TestReadFromArrayE(t *testing.T) {
+	array := []byte{0x01, 0x02}
+	mask := []byte{0x0F, 0x0F}
+	want := uint(0x12)
+
+	if got, e := ReadFromArrayE(array, mask); e != nil || got != want {
+		t.Errorf("ReadFromArrayE(%x, %x) = %x, '%s', want %x", array, mask, got, e, want)
+	}
+	if got, e := ReadE[int8](array, mask); e != nil || got != int8(want) {
+		t.Errorf("ReadE[int8](%x, %x) = %x, '%s', want %x", array, mask, got, e, want)
+	}
+
+	// Truncated array
+	array = []byte{0x01}
+	if _, e := ReadFromArrayE(array, mask); e != ErrArrayShorterThanMask {
+		t.Errorf("ReadFromArrayE(%x, %x) didn't throw '%s', but '%s'",
+			array, mask, ErrArrayShorterThanMask, e)
+	}
+	if _, e := ReadE[uint16](array, mask); e != ErrArrayShorterThanMask {
+		t.Errorf("ReadE[uint16](%x, %x) didn't throw '%s', but '%s'",
+			array, mask, ErrArrayShorterThanMask, e)
+	}
+
+	// Mask wider than an uint
+	array = make([]byte, 9)
+	mask = bytes.Repeat([]byte{0xFF}, 9)
+	if _, e := ReadFromArrayE(array, mask); e != ErrMaskTooWide {
+		t.Errorf("ReadFromArrayE(%x, %x) didn't throw '%s', but '%s'",
+			array, mask, ErrMaskTooWide, e)
+	}
+
+	array = []byte{0x12, 0x34}
+	masks := []MaskTypePair{
+		{[]byte{0xFF}, reflect.Uint8, 0, 0},
+		{[]byte{0x00, 0xFF}, reflect.Int8, 0, 0}}
+	wantValues := []interface{}{uint8(0x12), int8(0x34)}
+	if got, e := MultReadFromArrayE(array, masks...); e != nil || !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("MultReadFromArrayE(%x, %x) = %x, '%s', want %x", array, masks, got, e, wantValues)
+	}
+	masks = append(masks, MaskTypePair{[]byte{0x00, 0x00, 0xFF}, reflect.Uint8, 0, 0})
+	if _, e := MultReadFromArrayE(array, masks...); e != ErrArrayShorterThanMask {
+		t.Errorf("MultReadFromArrayE(%x, %x) didn't throw '%s', but '%s'",
+			array, masks, ErrArrayShorterThanMask, e)
+	}
+	if _, e := MultReadE[uint8](array, []byte{0xFF}, []byte{0x00, 0x00, 0xFF}); e != ErrArrayShorterThanMask {
+		t.Errorf("MultReadE[uint8](%x, ...) didn't throw '%s', but '%s'",
+			array, ErrArrayShorterThanMask, e)
+	}
+}
+
 func TestReadFromArrayTypeSpecifics(t *testing.T) {
 	array := []byte{0x01, 0x02}
 	mask := []byte{0x0F, 0x0F}
@@ -60,6 +311,65 @@ func TestReadFromArrayTypeSpecifics(t *testing.T) {
 	}
 }
 
+func TestReadFromArraySigned(t *testing.T) {
+	// 12-bit two's complement field in the low nibbles of three bytes
+	array := []byte{0x0F, 0x0F, 0x06}
+	mask := []byte{0x0F, 0x0F, 0x0F}
+	want := -10
+
+	if got := ReadFromArrayS(array, mask); got != want {
+		t.Errorf("ReadFromArrayS(%x, %x) = %d, want %d", array, mask, got, want)
+	}
+	if got := ReadFromArray8S(array, mask); got != int8(want) {
+		t.Errorf("ReadFromArray8S(%x, %x) = %d, want %d", array, mask, got, int8(want))
+	}
+	if got := ReadFromArray16S(array, mask); got != int16(want) {
+		t.Errorf("ReadFromArray16S(%x, %x) = %d, want %d", array, mask, got, int16(want))
+	}
+	if got := ReadFromArray32S(array, mask); got != int32(want) {
+		t.Errorf("ReadFromArray32S(%x, %x) = %d, want %d", array, mask, got, int32(want))
+	}
+	if got := ReadFromArray64S(array, mask); got != int64(want) {
+		t.Errorf("ReadFromArray64S(%x, %x) = %d, want %d", array, mask, got, int64(want))
+	}
+
+	// Positive values are not extended
+	array = []byte{0x07, 0x0F, 0x0F}
+	want = 0x7FF
+	if got := ReadFromArray16S(array, mask); got != int16(want) {
+		t.Errorf("ReadFromArray16S(%x, %x) = %d, want %d", array, mask, got, int16(want))
+	}
+}
+
+func TestWriteToArraySigned(t *testing.T) {
+	mask := []byte{0x0F, 0x0F, 0x0F}
+	value := int16(-10)
+	want := []byte{0x0F, 0x0F, 0x06}
+
+	array := make([]byte, 3)
+	if got, e := WriteToArray16S(array, mask, value); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteToArray16S(%x, %x, %d) = %x, want %x", array, mask, value, got, want)
+	}
+	if got := ReadFromArray16S(want, mask); got != value {
+		t.Errorf("ReadFromArray16S(%x, %x) = %d, want %d", want, mask, got, value)
+	}
+
+	// Range is checked against the width of the mask, not the Go type
+	for _, v := range []int16{-2049, 2048} {
+		array = make([]byte, 3)
+		if _, e := WriteToArray16S(array, mask, v); e != ErrNotEnoughBitsToEmbedValue {
+			t.Errorf("WriteToArray16S(%x, %x, %d) didn't throw '%s', but '%s'",
+				array, mask, v, ErrNotEnoughBitsToEmbedValue, e)
+		}
+	}
+	for _, v := range []int16{-2048, 2047} {
+		array = make([]byte, 3)
+		if _, e := WriteToArray16S(array, mask, v); e != nil {
+			t.Errorf("WriteToArray16S(%x, %x, %d) threw '%s'", array, mask, v, e)
+		}
+	}
+}
+
 func TestWriteToArray(t *testing.T) {
 	array := []byte{0x00, 0x00}
 	mask := []byte{0x0F, 0x0F}
@@ -97,6 +407,96 @@ func TestWriteToArray(t *testing.T) {
 	}
 }
 
+func TestReplaceInArray(t *testing.T) {
+	mask := []byte{0x0F, 0x0F}
+	value := uint(0x12)
+
+	// Writing ORs into the bits already set
+	array := []byte{0xF4, 0x08}
+	want := []byte{0xF5, 0x0A}
+	if got, e := WriteToArray(array, mask, value); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteToArray(%x, %x, %x) = %x, want %x", array, mask, value, got, want)
+	}
+
+	// Replacing clears the masked bits first, leaving the rest untouched
+	array = []byte{0xF4, 0x08}
+	want = []byte{0xF1, 0x02}
+	if got, e := ReplaceInArray(array, mask, value); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("ReplaceInArray(%x, %x, %x) = %x, want %x", array, mask, value, got, want)
+	}
+
+	// Errors leave the array untouched
+	array = []byte{0xF4, 0x08}
+	want = []byte{0xF4, 0x08}
+	if got, e := ReplaceInArray(array, mask, 0x123); e != ErrNotEnoughBitsToEmbedValue || !bytes.Equal(got, want) {
+		t.Errorf("ReplaceInArray(%x, %x, %x) = %x, '%s', want %x, '%s'",
+			array, mask, 0x123, got, e, want, ErrNotEnoughBitsToEmbedValue)
+	}
+
+	// Repeated writes into one buffer
+	array = []byte{0x81, 0x01, 0x04, 0x47, 0x00, 0x00, 0xFF}
+	mask = []byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x00}
+	for _, v := range []int8{0x24, 0x13, -1} {
+		want = []byte{0x81, 0x01, 0x04, 0x47, byte(v>>4) & 0x0F, byte(v) & 0x0F, 0xFF}
+		if got, e := Replace(array, mask, v); e != nil || !bytes.Equal(got, want) {
+			t.Errorf("Replace(%x, %x, %d) = %x, want %x", array, mask, v, got, want)
+		}
+	}
+
+	array = []byte{0xFF, 0xFF}
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0xF0, 0xF0}, 0x12, 0},
+		MaskValuePair8{[]byte{0x0F, 0x0F}, 0x34, 0},
+	}
+	want = []byte{0x13, 0x24}
+	if got, e := MultReplaceInArray(array, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultReplaceInArray(%x, %x) = %x, want %x", array, maskValuePairs, got, want)
+	}
+}
+
+func TestWriteToCopy(t *testing.T) {
+	template := []byte{0x81, 0x01, 0x04, 0x47, 0x00, 0x00, 0xFF}
+	mask := []byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x00}
+	original := []byte{0x81, 0x01, 0x04, 0x47, 0x00, 0x00, 0xFF}
+	want := []byte{0x81, 0x01, 0x04, 0x47, 0x02, 0x04, 0xFF}
+
+	if got, e := WriteToCopy(template, mask, 0x24); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteToCopy(%x, %x, %x) = %x, want %x", template, mask, 0x24, got, want)
+	}
+	if got, e := WriteCopy(template, mask, int8(0x24)); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteCopy(%x, %x, %x) = %x, want %x", template, mask, 0x24, got, want)
+	}
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x00}, 0x2, 0},
+		MaskValuePair8{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00}, 0x4, 0},
+	}
+	if got, e := MultWriteToCopy(template, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultWriteToCopy(%x, %x) = %x, want %x", template, maskValuePairs, got, want)
+	}
+
+	if !bytes.Equal(template, original) {
+		t.Errorf("template modified to %x, want %x", template, original)
+	}
+
+	// Concurrent writes to a shared template
+	done := make(chan []byte)
+	for i := 0; i < 8; i++ {
+		go func(v uint8) {
+			got, _ := WriteCopy(template, mask, v)
+			done <- got
+		}(uint8(i))
+	}
+	for i := 0; i < 8; i++ {
+		got := <-done
+		if v := ReadFromArray8(got, mask); got[4] != v>>4 || got[5] != v&0x0F {
+			t.Errorf("WriteCopy(%x, %x, %x) = %x", template, mask, v, got)
+		}
+	}
+	if !bytes.Equal(template, original) {
+		t.Errorf("template modified to %x, want %x", template, original)
+	}
+}
+
 func TestWriteToArrayTypeSpecifics(t *testing.T) {
 	array := []byte{0x00, 0x00}
 	mask := []byte{0x0F, 0x0F}
@@ -120,10 +520,10 @@ func TestWriteToArrayTypeSpecifics(t *testing.T) {
 func TestMultReadFromArray(t *testing.T) {
 	array := []byte{0x12, 0x34, 0x56, 0x78}
 	masks := []MaskTypePair{
-		{[]byte{0xF0, 0xF0, 0x00, 0x00}, reflect.Uint},
-		{[]byte{0x0F, 0x0F, 0x00, 0x00}, reflect.Uint},
-		{[]byte{0xFF, 0x00, 0xFF, 0x00}, reflect.Uint},
-		{[]byte{0x00, 0xFF, 0x00, 0x0F}, reflect.Uint}}
+		{[]byte{0xF0, 0xF0, 0x00, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0x0F, 0x0F, 0x00, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0xFF, 0x00, 0xFF, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0x00, 0xFF, 0x00, 0x0F}, reflect.Uint, 0, 0}}
 	want := []interface{}{
 		uint(0x13),
 		uint(0x24),
@@ -139,20 +539,25 @@ func TestMultReadFromArray(t *testing.T) {
 func TestMultReadFromArrayTypeSpecifics(t *testing.T) {
 	array := []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
 	masks := []MaskTypePair{
-		{[]byte{0xF0, 0xF0, 0x00, 0x00}, reflect.Uint},
-		{[]byte{0x0F, 0x0F, 0x00, 0x00}, reflect.Uint},
-		{[]byte{0xFF, 0x00, 0xFF, 0x00}, reflect.Uint},
-		{[]byte{0x00, 0xFF, 0x00, 0x0F}, reflect.Uint},
-		{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint},
-		{[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint}}
+		{[]byte{0xF0, 0xF0, 0x00, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0x0F, 0x0F, 0x00, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0xFF, 0x00, 0xFF, 0x00}, reflect.Uint, 0, 0},
+		{[]byte{0x00, 0xFF, 0x00, 0x0F}, reflect.Uint, 0, 0},
+		{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint, 0, 0}}
 	want := []interface{}{
 		uint(0x13),
 		uint(0x24),
 		uint(0x1256),
 		uint(0x348),
 		uint(0x12345678),
-		uint(0x123456789A),
 	}
+	wide := uint64(0x123456789A)
+	if bits.UintSize == 64 {
+		masks = append(masks, MaskTypePair{[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint, 0, 0})
+		want = append(want, uint(wide))
+	}
+	masks = append(masks, MaskTypePair{[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint64, 0, 0})
+	want = append(want, wide)
 	if got := MultReadFromArray(array, masks...); !reflect.DeepEqual(want, got) {
 		t.Errorf("MultReadFromArray(%x, %x) = %x, want %x", array, masks, got, want)
 	}
@@ -219,11 +624,11 @@ func TestMultWriteToArray(t *testing.T) {
 	// Unsigned values
 	array := []byte{0x00, 0x00, 0x00, 0x00, 0x00}
 	maskValuePairs := []interface{}{
-		MaskValuePair{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF}, 0x9A},
-		MaskValuePair8{[]byte{0x00, 0x00, 0x00, 0xFF}, 0x78},
-		MaskValuePair16{[]byte{0x00, 0x0F, 0x0F, 0x00}, 0x46},
-		MaskValuePair32{[]byte{0xF0, 0x00, 0xF0, 0x00}, 0x15},
-		MaskValuePair64{[]byte{0x0F, 0xF0, 0x00, 0x00}, 0x23},
+		MaskValuePair{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF}, 0x9A, 0},
+		MaskValuePair8{[]byte{0x00, 0x00, 0x00, 0xFF}, 0x78, 0},
+		MaskValuePair16{[]byte{0x00, 0x0F, 0x0F, 0x00}, 0x46, 0},
+		MaskValuePair32{[]byte{0xF0, 0x00, 0xF0, 0x00}, 0x15, 0},
+		MaskValuePair64{[]byte{0x0F, 0xF0, 0x00, 0x00}, 0x23, 0},
 	}
 	want := []byte{0x12, 0x34, 0x56, 0x78, 0x9A}
 	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !reflect.DeepEqual(got, want) {
@@ -233,11 +638,11 @@ func TestMultWriteToArray(t *testing.T) {
 	// Signed values
 	array = make([]byte, 24)
 	maskValuePairs = []interface{}{
-		MaskValuePairS{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, -1},
-		MaskValuePair8S{[]byte{0x00, 0x00, 0x00, 0xFF}, -43},
-		MaskValuePair16S{[]byte{0x00, 0xFF, 0xFF, 0x00}, -1345},
-		MaskValuePair32S{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}, -705422},
-		MaskValuePair64S{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, -12134142},
+		MaskValuePairS{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, -1, 0},
+		MaskValuePair8S{[]byte{0x00, 0x00, 0x00, 0xFF}, -43, 0},
+		MaskValuePair16S{[]byte{0x00, 0xFF, 0xFF, 0x00}, -1345, 0},
+		MaskValuePair32S{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}, -705422, 0},
+		MaskValuePair64S{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, -12134142, 0},
 	}
 	want = []byte{0x00, 0xFA, 0xBF, 0xD5, 0xFF, 0xF5, 0x3C, 0x72, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x46, 0xD9, 0x02}
 	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !reflect.DeepEqual(got, want) {
@@ -250,8 +655,8 @@ func TestMultWriteToArray(t *testing.T) {
 	// Float values
 	array = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
 	maskValuePairs = []interface{}{
-		MaskValuePair32F{[]byte{0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0x00, 0x00}, 1.00},
-		MaskValuePair32F{[]byte{0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x00, 0x00}, 1.50},
+		MaskValuePair32F{[]byte{0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0x00, 0x00}, 1.00, 0},
+		MaskValuePair32F{[]byte{0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x0F, 0xF0, 0x00, 0x00}, 1.50, 0},
 	}
 	want = []byte{0x33, 0xFF, 0x8C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
 	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !reflect.DeepEqual(got, want) {
@@ -273,8 +678,8 @@ func TestMultWriteToArray(t *testing.T) {
 
 	array = make([]byte, 12)
 	maskValuePairs = []interface{}{
-		MaskValuePair64F{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0.25},
-		MaskValuePair32F{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, 0.50},
+		MaskValuePair64F{[]byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0.25, 0},
+		MaskValuePair32F{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, 0.50, 0},
 	}
 	want = []byte{0x3f, 0x00, 0x00, 0x00, 0x3f, 0xd0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
 	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !reflect.DeepEqual(got, want) {
@@ -286,6 +691,329 @@ func TestMultWriteToArray(t *testing.T) {
 
 }
 
+func TestGeneric(t *testing.T) {
+	type offset int16
+
+	array := []byte{0x0F, 0x0F, 0x06}
+	mask := []byte{0x0F, 0x0F, 0x0F}
+	want := offset(-10)
+
+	if got := Read[offset](array, mask); got != want {
+		t.Errorf("Read[offset](%x, %x) = %d, want %d", array, mask, got, want)
+	}
+
+	buffer := make([]byte, 3)
+	if got, e := Write(buffer, mask, want); e != nil || !bytes.Equal(got, array) {
+		t.Errorf("Write(%x, %x, %d) = %x, want %x", buffer, mask, want, got, array)
+	}
+
+	masks := [][]byte{{0xFF, 0x00, 0x00}, {0x0F, 0x0F, 0x00}}
+	want8 := []int8{15, -1}
+	if got := MultRead[int8](array, masks...); !reflect.DeepEqual(want8, got) {
+		t.Errorf("MultRead[int8](%x, %x) = %d, want %d", array, masks, got, want8)
+	}
+
+	buffer = make([]byte, 3)
+	maskValuePairs := []interface{}{
+		MaskValue[offset]{mask, want, 0},
+		MaskValue[uint16]{[]byte{0xF0, 0xF0, 0x00}, 0x1, 0},
+	}
+	wantArray := []byte{0x0F, 0x1F, 0x06}
+	if got, e := MultWriteToArray(buffer, maskValuePairs...); e != nil || !bytes.Equal(got, wantArray) {
+		t.Errorf("MultWriteToArray(%x, %x) = %x, want %x", buffer, maskValuePairs, got, wantArray)
+	}
+
+	if _, e := MultWriteToArray(buffer, "not a pair"); !errors.Is(e, ErrInterfaceTypeNotSupported) {
+		t.Errorf("MultWriteToArray(%x, string) didn't throw '%s', but '%s'",
+			buffer, ErrInterfaceTypeNotSupported, e)
+	}
+}
+
+func TestMultWriteToArrayAtomic(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []interface{}
+		want  error
+	}{
+		{"value too wide", []interface{}{
+			MaskValuePair8{[]byte{0xF0}, 0x1, 0},
+			MaskValuePair8{[]byte{0x00, 0x0F}, 0x2, 0},
+			MaskValuePair8{[]byte{0x00, 0x00, 0x0F}, 0x30, 0},
+		}, ErrNotEnoughBitsToEmbedValue},
+		{"array too short", []interface{}{
+			MaskValuePair8{[]byte{0xF0}, 0x1, 0},
+			MaskValuePair8{[]byte{0x0F}, 0x2, 3},
+		}, ErrArrayShorterThanMask},
+		{"unsupported pair", []interface{}{
+			MaskValuePair8{[]byte{0xF0}, 0x1, 0},
+			"not a pair",
+		}, ErrInterfaceTypeNotSupported},
+	}
+
+	for _, tt := range tests {
+		array := []byte{0x0A, 0xB0, 0xC0}
+		want := []byte{0x0A, 0xB0, 0xC0}
+
+		if got, e := MultWriteToArray(array, tt.pairs...); !errors.Is(e, tt.want) || !bytes.Equal(got, want) || !bytes.Equal(array, want) {
+			t.Errorf("MultWriteToArray with %s = %x, '%v', want %x, '%s'", tt.name, array, e, want, tt.want)
+		}
+		if got, e := MultReplaceInArray(array, tt.pairs...); !errors.Is(e, tt.want) || !bytes.Equal(got, want) || !bytes.Equal(array, want) {
+			t.Errorf("MultReplaceInArray with %s = %x, '%v', want %x, '%s'", tt.name, array, e, want, tt.want)
+		}
+	}
+}
+
+func TestMultWriteToArrayAllocs(t *testing.T) {
+	array := make([]byte, 64)
+	pairs := []interface{}{
+		MaskValuePair8{[]byte{0xF0}, 0x1, 0},
+		MaskValuePair16S{[]byte{0x0F, 0xFF}, -2, 8},
+	}
+	if allocs := testing.AllocsPerRun(100, func() { MultWriteToArray(array, pairs...) }); allocs != 0 {
+		t.Errorf("MultWriteToArray allocates %.0f times, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { MultWriteToCopy(array, pairs...) }); allocs != 1 {
+		t.Errorf("MultWriteToCopy allocates %.0f times, want 1", allocs)
+	}
+}
+
+func TestMultWriteToArrayFieldError(t *testing.T) {
+	array := []byte{0x00, 0x00, 0x00}
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0xF0}, 0x1, 0},
+		MaskValuePair16{[]byte{0x00, 0x0F}, 0x20, 0},
+		"not a pair",
+		MaskValuePair8{[]byte{0x00, 0x00, 0x0F}, 0x30, 0},
+	}
+
+	// Stops at the first failing pair
+	_, e := MultWriteToArray(array, maskValuePairs...)
+	var fe *FieldError
+	if !errors.As(e, &fe) || fe.Index != 1 || !bytes.Equal(fe.Mask, []byte{0x00, 0x0F}) || fe.Value != uint16(0x20) ||
+		!errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+		t.Errorf("MultWriteToArray(%x, %v) = '%v', want field 1 '%s'", array, maskValuePairs, e, ErrNotEnoughBitsToEmbedValue)
+	}
+
+	// Reports every failing pair
+	for _, f := range []func([]byte, ...interface{}) ([]byte, error){MultWriteToArrayAll, MultReplaceInArrayAll} {
+		got, e := f(array, maskValuePairs...)
+		if !bytes.Equal(got, []byte{0x00, 0x00, 0x00}) {
+			t.Errorf("MultWriteToArrayAll(%x, %v) = %x, want %x", array, maskValuePairs, got, array)
+		}
+
+		var indices []int
+		for _, err := range e.(interface{ Unwrap() []error }).Unwrap() {
+			if errors.As(err, &fe) {
+				indices = append(indices, fe.Index)
+			}
+		}
+		if !reflect.DeepEqual(indices, []int{1, 2, 3}) {
+			t.Errorf("MultWriteToArrayAll(%x, %v) reported fields %v, want %v", array, maskValuePairs, indices, []int{1, 2, 3})
+		}
+		if !errors.Is(e, ErrInterfaceTypeNotSupported) || !errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+			t.Errorf("MultWriteToArrayAll(%x, %v) = '%v', want both '%s' and '%s'",
+				array, maskValuePairs, e, ErrInterfaceTypeNotSupported, ErrNotEnoughBitsToEmbedValue)
+		}
+	}
+
+	if _, e := MultWriteToArrayAll(array, maskValuePairs[0]); e != nil {
+		t.Errorf("MultWriteToArrayAll(%x, %v) = '%v', want nil", array, maskValuePairs[0], e)
+	}
+}
+
+func TestReadWriteWide(t *testing.T) {
+	tests := []struct {
+		mask  []byte
+		value uint64
+		array []byte
+	}{
+		// 33 bits
+		{[]byte{0x01, 0xFF, 0xFF, 0xFF, 0xFF}, 0x1FEDCBA98,
+			[]byte{0x01, 0xFE, 0xDC, 0xBA, 0x98}},
+		// 40 bits in nibbles
+		{[]byte{0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F}, 0x123456789A,
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A}},
+		// 48 bits
+		{[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0xFEDCBA987654,
+			[]byte{0xFE, 0xDC, 0xBA, 0x98, 0x76, 0x54}},
+		// 63 bits
+		{[]byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0x7EDCBA9876543210,
+			[]byte{0x7E, 0xDC, 0xBA, 0x98, 0x76, 0x54, 0x32, 0x10}},
+		// 64 bits
+		{[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0xFEDCBA9876543210,
+			[]byte{0xFE, 0xDC, 0xBA, 0x98, 0x76, 0x54, 0x32, 0x10}},
+	}
+
+	for _, tt := range tests {
+		if got := ReadFromArray64(tt.array, tt.mask); got != tt.value {
+			t.Errorf("ReadFromArray64(%x, %x) = %x, want %x", tt.array, tt.mask, got, tt.value)
+		}
+		if got, e := ReadE[uint64](tt.array, tt.mask); e != nil || got != tt.value {
+			t.Errorf("ReadE[uint64](%x, %x) = %x, '%v', want %x", tt.array, tt.mask, got, e, tt.value)
+		}
+		if got := CompileMask(tt.mask).Read64(tt.array); got != tt.value {
+			t.Errorf("CompiledMask.Read64(%x, %x) = %x, want %x", tt.array, tt.mask, got, tt.value)
+		}
+
+		array := make([]byte, len(tt.mask))
+		if got, e := WriteToArray64(array, tt.mask, tt.value); e != nil || !bytes.Equal(got, tt.array) {
+			t.Errorf("WriteToArray64(%x, %x) = %x, '%v', want %x", tt.mask, tt.value, got, e, tt.array)
+		}
+		array = make([]byte, len(tt.mask))
+		if got, e := CompileMask(tt.mask).Write64(array, tt.value); e != nil || !bytes.Equal(got, tt.array) {
+			t.Errorf("CompiledMask.Write64(%x, %x) = %x, '%v', want %x", tt.mask, tt.value, got, e, tt.array)
+		}
+
+		// One bit too many
+		if width := CountOnes(tt.mask); width < 64 {
+			if _, e := WriteToArray64(make([]byte, len(tt.mask)), tt.mask, 1<<uint(width)); e != ErrNotEnoughBitsToEmbedValue {
+				t.Errorf("WriteToArray64(%x, %x) didn't throw '%s', but '%s'",
+					tt.mask, uint64(1)<<uint(width), ErrNotEnoughBitsToEmbedValue, e)
+			}
+		}
+	}
+
+	// Signed values wider than 32 bits
+	mask := []byte{0x0F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	array := []byte{0x0F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE}
+	if got := ReadFromArray64S(array, mask); got != -2 {
+		t.Errorf("ReadFromArray64S(%x, %x) = %d, want %d", array, mask, got, -2)
+	}
+	if got, e := WriteToArray64S(make([]byte, 6), mask, -2); e != nil || !bytes.Equal(got, array) {
+		t.Errorf("WriteToArray64S(%x, %d) = %x, '%v', want %x", mask, -2, got, e, array)
+	}
+	if _, e := WriteToArray64S(make([]byte, 6), mask, -1<<43-1); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteToArray64S(%x, %d) didn't throw '%s', but '%s'", mask, int64(-1)<<43-1, ErrNotEnoughBitsToEmbedValue, e)
+	}
+
+	// float64 spread over nibbles of 16 bytes
+	mask = bytes.Repeat([]byte{0x0F}, 16)
+	array = make([]byte, 16)
+	if _, e := WriteToArray64F(array, mask, -1.5); e != nil || ReadFromArray64F(array, mask) != -1.5 {
+		t.Errorf("ReadFromArray64F(%x, %x) = %v, '%v', want %v", array, mask, ReadFromArray64F(array, mask), e, -1.5)
+	}
+
+	// More than 64 bits can't be read
+	mask = bytes.Repeat([]byte{0xFF}, 9)
+	if _, e := ReadE[uint64](make([]byte, 9), mask); e != ErrMaskTooWide {
+		t.Errorf("ReadE[uint64](%x) didn't throw '%s', but '%s'", mask, ErrMaskTooWide, e)
+	}
+
+	// and are read as 0 by the functions without errors
+	array = bytes.Repeat([]byte{0xFF}, 9)
+	pairs := []MaskTypePair{{mask, reflect.Uint64, 0, 0}, {mask, reflect.Int64, 0, 0}}
+	if got := ReadFromArray(array, mask); got != 0 {
+		t.Errorf("ReadFromArray(%x, %x) = %x, want 0", array, mask, got)
+	}
+	if got := ReadFromArray64S(array, mask); got != 0 {
+		t.Errorf("ReadFromArray64S(%x, %x) = %x, want 0", array, mask, got)
+	}
+	if got := CompileMask(mask).Read64(array); got != 0 {
+		t.Errorf("CompiledMask.Read64(%x, %x) = %x, want 0", array, mask, got)
+	}
+	if got := MultReadFromArray(array, pairs...); !reflect.DeepEqual(got, []interface{}{uint64(0), int64(0)}) {
+		t.Errorf("MultReadFromArray(%x, %v) = %v, want 0, 0", array, pairs, got)
+	}
+	if got := ReadScaled(array, mask, 0.5, 1, false); got != 1 {
+		t.Errorf("ReadScaled(%x, %x) = %v, want 1", array, mask, got)
+	}
+}
+
+func TestWriteOverflow(t *testing.T) {
+	mask := []byte{0x0F, 0x0F}
+	tests := []struct {
+		value    uint
+		overflow Overflow
+		want     []byte
+		err      error
+	}{
+		{0x24, OverflowError, []byte{0x02, 0x04}, nil},
+		{0x124, OverflowError, []byte{0x00, 0x00}, ErrNotEnoughBitsToEmbedValue},
+		{0x124, OverflowTruncate, []byte{0x02, 0x04}, nil},
+		{0x124, OverflowSaturate, []byte{0x0F, 0x0F}, nil},
+		{0x24, OverflowSaturate, []byte{0x02, 0x04}, nil},
+	}
+
+	for _, tt := range tests {
+		array := []byte{0x00, 0x00}
+		if got, e := WriteToArrayOverflow(array, mask, tt.value, tt.overflow); e != tt.err || !bytes.Equal(array, tt.want) {
+			t.Errorf("WriteToArrayOverflow(%x, %x, %d) = %x, '%v', want %x, '%v'", mask, tt.value, tt.overflow, got, e, tt.want, tt.err)
+		}
+	}
+
+	signed := []struct {
+		value    int16
+		overflow Overflow
+		want     int16
+	}{
+		{-200, OverflowTruncate, 0x38},
+		{300, OverflowTruncate, 0x2C},
+		{-200, OverflowSaturate, -128},
+		{200, OverflowSaturate, 127},
+		{-5, OverflowSaturate, -5},
+	}
+
+	for _, tt := range signed {
+		array := []byte{0x00, 0x00}
+		if _, e := WriteOverflow(array, mask, tt.value, tt.overflow); e != nil || Read[int16](array, mask) != tt.want {
+			t.Errorf("WriteOverflow(%x, %d, %d) = %x, '%v', want %d", mask, tt.value, tt.overflow, array, e, tt.want)
+		}
+	}
+
+	// Saturating every signed width through the generic function
+	wideMask := []byte{0x0F, 0xFF}
+	if got, e := WriteOverflow(make([]byte, 1), []byte{0x0F}, int8(-100), OverflowSaturate); e != nil || Read[int8](got, []byte{0x0F}) != -8 {
+		t.Errorf("WriteOverflow(0f, int8(-100)) = %x, '%v', want -8", got, e)
+	}
+	if got, e := WriteOverflow(make([]byte, 2), wideMask, int16(3000), OverflowSaturate); e != nil || Read[int16](got, wideMask) != 2047 {
+		t.Errorf("WriteOverflow(%x, int16(3000)) = %x, '%v', want 2047", wideMask, got, e)
+	}
+	if got, e := WriteOverflow(make([]byte, 2), wideMask, int32(-3000), OverflowSaturate); e != nil || Read[int32](got, wideMask) != -2048 {
+		t.Errorf("WriteOverflow(%x, int32(-3000)) = %x, '%v', want -2048", wideMask, got, e)
+	}
+	if got, e := WriteOverflow(make([]byte, 2), wideMask, int64(1)<<40, OverflowSaturate); e != nil || Read[int64](got, wideMask) != 2047 {
+		t.Errorf("WriteOverflow(%x, int64(1<<40)) = %x, '%v', want 2047", wideMask, got, e)
+	}
+	if got, e := WriteOverflow(make([]byte, 2), wideMask, int(-1)<<20, OverflowSaturate); e != nil || Read[int](got, wideMask) != -2048 {
+		t.Errorf("WriteOverflow(%x, int(-1<<20)) = %x, '%v', want -2048", wideMask, got, e)
+	}
+
+	// Saturating the full width of the type
+	if got, e := WriteOverflow([]byte{0x00}, []byte{0xFF}, int64(-1000), OverflowSaturate); e != nil || Read[int8](got, []byte{0xFF}) != -128 {
+		t.Errorf("WriteOverflow(ff, -1000) = %x, '%v', want %x", got, e, []byte{0x80})
+	}
+
+	// Floats still need the full bit pattern
+	if _, e := WriteOverflow([]byte{0x00, 0x00}, mask, float32(1), OverflowSaturate); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteOverflow(%x, float32) didn't throw '%s', but '%s'", mask, ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
+
+func TestMultWriteToArrayOverflow(t *testing.T) {
+	array := []byte{0x00, 0x00, 0x00}
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0xF0}, 0x12, 0},
+		MaskValuePair8S{[]byte{0x00, 0x0F}, -9, 0},
+		MaskValuePairScaled{[]byte{0x00, 0x00, 0xFF}, 300, 1, 0, false, 0},
+	}
+	want := []byte{0xF0, 0x08, 0xFF}
+
+	if got, e := MultWriteToArrayOverflow(array, OverflowSaturate, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultWriteToArrayOverflow(%x, %v) = %x, '%v', want %x", array, maskValuePairs, got, e, want)
+	}
+
+	array = []byte{0x00, 0x00, 0x00}
+	want = []byte{0x20, 0x07, 0x2C}
+	if got, e := MultWriteToArrayOverflow(array, OverflowTruncate, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultWriteToArrayOverflow(%x, %v) = %x, '%v', want %x", array, maskValuePairs, got, e, want)
+	}
+
+	array = []byte{0x00, 0x00, 0x00}
+	if _, e := MultWriteToArrayOverflow(array, OverflowError, maskValuePairs...); !errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+		t.Errorf("MultWriteToArrayOverflow(%x, %v) didn't throw '%s', but '%v'", array, maskValuePairs, ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
+
 func BenchmarkReadFromArray(b *testing.B) {
 	array := []byte{0x81, 0x09, 0x04, 0x4A, 0x00, 0x00, 0x05, 0x01, 0xFF}
 	mask := []byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x0F, 0x0F, 0x00}
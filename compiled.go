@@ -0,0 +1,129 @@
+package bitbytepack
+
+import "math/bits"
+
+// Mask with the shifts of every byte computed once, for reading and writing
+// the same mask repeatedly. Behaves like ReadFromArray and WriteToArray.
+type CompiledMask struct {
+	parts  []maskPart
+	length int  // length of the mask
+	width  int  // number of bits in the mask
+	shift  uint // final shift of the value read
+}
+
+// Non-zero byte of a compiled mask
+type maskPart struct {
+	index    int  // index in the array
+	mask     byte // mask byte
+	left     uint // shift of the masked byte all the way to the left
+	right    uint // shift to fit after the bits of the preceding bytes
+	trailing uint // trailing zeros of the mask byte
+	ones     uint // bits in the mask byte
+}
+
+// Compile a mask
+func CompileMask(mask []byte) *CompiledMask {
+	c := &CompiledMask{length: len(mask)}
+
+	for i, m := range mask {
+		if m == 0 {
+			continue
+		}
+		c.parts = append(c.parts, maskPart{
+			index:    i,
+			mask:     m,
+			left:     uint((64 - 8) + bits.LeadingZeros8(m)),
+			right:    uint(c.width),
+			trailing: uint(bits.TrailingZeros8(m)),
+			ones:     uint(bits.OnesCount8(m)),
+		})
+		c.width += bits.OnesCount8(m)
+	}
+
+	if c.width < 64 {
+		c.shift = uint(64 - c.width)
+	}
+	return c
+}
+
+// Number of bits in the mask
+func (c *CompiledMask) Width() int {
+	return c.width
+}
+
+// Read the value of the array, see ReadFromArray
+func (c *CompiledMask) Read(array []byte) uint {
+	return uint(c.Read64(array))
+}
+
+// Like Read, but reads up to 64 bits regardless of the size of uint
+func (c *CompiledMask) Read64(array []byte) uint64 {
+	if len(array) < c.length || c.width > 64 {
+		return 0
+	}
+
+	var value uint64 = 0
+	for _, p := range c.parts {
+		value += (uint64(array[p.index]&p.mask) << p.left) >> p.right
+	}
+	return value >> c.shift
+}
+
+// Write the value to the array, see WriteToArray
+func (c *CompiledMask) Write(array []byte, value uint) ([]byte, error) {
+	return c.write(array, uint64(value), writeOptions{})
+}
+
+// Like Write, but takes a 64-bit value regardless of the size of uint
+func (c *CompiledMask) Write64(array []byte, value uint64) ([]byte, error) {
+	return c.write(array, value, writeOptions{})
+}
+
+// Like Write, but with values too wide for the mask handled as given by
+// overflow, see WriteToArrayOverflow
+func (c *CompiledMask) WriteOverflow(array []byte, value uint, overflow Overflow) ([]byte, error) {
+	return c.write(array, uint64(value), writeOptions{overflow: overflow})
+}
+
+// Like WriteOverflow, but takes a 64-bit value regardless of the size of uint
+func (c *CompiledMask) WriteOverflow64(array []byte, value uint64, overflow Overflow) ([]byte, error) {
+	return c.write(array, value, writeOptions{overflow: overflow})
+}
+
+// Replace the value in the array, see ReplaceInArray
+func (c *CompiledMask) Replace(array []byte, value uint) ([]byte, error) {
+	return c.write(array, uint64(value), writeOptions{replace: true})
+}
+
+// Like Replace, but takes a 64-bit value regardless of the size of uint
+func (c *CompiledMask) Replace64(array []byte, value uint64) ([]byte, error) {
+	return c.write(array, value, writeOptions{replace: true})
+}
+
+func (c *CompiledMask) write(array []byte, value uint64, opts writeOptions) ([]byte, error) {
+	if len(array) < c.length {
+		return []byte{}, ErrArrayShorterThanMask
+	}
+
+	if c.width < bits.Len64(value) {
+		switch opts.overflow {
+		case OverflowTruncate:
+			value &= (1 << uint(c.width)) - 1
+		case OverflowSaturate:
+			value = (1 << uint(c.width)) - 1
+		default:
+			return array, ErrNotEnoughBitsToEmbedValue
+		}
+	}
+
+	// Reverse iteration, from the least significant byte
+	for i := len(c.parts) - 1; i >= 0; i-- {
+		p := c.parts[i]
+		if opts.replace {
+			array[p.index] &^= p.mask
+		}
+		array[p.index] |= (byte(value) << p.trailing) & p.mask
+		value >>= p.ones
+	}
+	return array, nil
+}
@@ -0,0 +1,74 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompiledMask(t *testing.T) {
+	array := []byte{0x81, 0x09, 0x04, 0x4A, 0x0C, 0x3D, 0x05, 0x01, 0xFF}
+	masks := [][]byte{
+		{0x0F, 0x0F},
+		{0xF0, 0x0F},
+		{0x3C, 0x0F},
+		{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x0F, 0x0F, 0x00},
+		{0xFF, 0x00, 0xFF, 0x00, 0xFF},
+		{},
+	}
+
+	for _, mask := range masks {
+		c := CompileMask(mask)
+		if c.Width() != CountOnes(mask) {
+			t.Errorf("CompileMask(%x).Width() = %d, want %d", mask, c.Width(), CountOnes(mask))
+		}
+		if got, want := c.Read(array), ReadFromArray(array, mask); got != want {
+			t.Errorf("CompileMask(%x).Read(%x) = %x, want %x", mask, array, got, want)
+		}
+
+		value := ReadFromArray(array, mask)
+		want, _ := WriteToArray(make([]byte, len(array)), mask, value)
+		if got, e := c.Write(make([]byte, len(array)), value); e != nil || !bytes.Equal(got, want) {
+			t.Errorf("CompileMask(%x).Write(%x) = %x, want %x", mask, value, got, want)
+		}
+		want, _ = ReplaceInArray(clone(array), mask, value+1)
+		if got, e := c.Replace(clone(array), value+1); !bytes.Equal(got, want) {
+			t.Errorf("CompileMask(%x).Replace(%x) = %x, '%v', want %x", mask, value+1, got, e, want)
+		}
+	}
+
+	c := CompileMask([]byte{0x0F, 0x0F})
+	if got := c.Read([]byte{0x01}); got != 0 {
+		t.Errorf("CompileMask.Read(short array) = %x, want 0", got)
+	}
+	if _, e := c.Write([]byte{0x00}, 0x12); e != ErrArrayShorterThanMask {
+		t.Errorf("CompileMask.Write(short array) didn't throw '%s', but '%s'", ErrArrayShorterThanMask, e)
+	}
+	if _, e := c.Write([]byte{0x00, 0x00}, 0x123); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("CompileMask.Write(0x123) didn't throw '%s', but '%s'", ErrNotEnoughBitsToEmbedValue, e)
+	}
+	for _, overflow := range []Overflow{OverflowError, OverflowTruncate, OverflowSaturate} {
+		want, wantErr := WriteToArrayOverflow([]byte{0x00, 0x00}, []byte{0x0F, 0x0F}, 0x123, overflow)
+		if got, e := c.WriteOverflow([]byte{0x00, 0x00}, 0x123, overflow); e != wantErr || !bytes.Equal(got, want) {
+			t.Errorf("CompileMask.WriteOverflow(0x123, %d) = %x, '%v', want %x, '%v'", overflow, got, e, want, wantErr)
+		}
+	}
+}
+
+func BenchmarkCompiledMaskRead(b *testing.B) {
+	array := []byte{0x81, 0x09, 0x04, 0x4A, 0x00, 0x00, 0x05, 0x01, 0xFF}
+	mask := CompileMask([]byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x0F, 0x0F, 0x00})
+
+	for i := 0; i < b.N; i++ {
+		mask.Read(array)
+	}
+}
+
+func BenchmarkCompiledMaskWrite(b *testing.B) {
+	array := []byte{0x81, 0x09, 0x04, 0x4A, 0x00, 0x00, 0x00, 0x00, 0xFF}
+	mask := CompileMask([]byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x0F, 0x0F, 0x00})
+	value := uint(0x1234)
+
+	for i := 0; i < b.N; i++ {
+		mask.Write(array, value)
+	}
+}
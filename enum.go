@@ -0,0 +1,77 @@
+package bitbytepack
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Enumerated field values, mapping integer codes to names
+type Enum struct {
+	names map[uint]string
+	codes map[string]uint
+}
+
+// Create an Enum from the names of each code. Names must be unique.
+func NewEnum(names map[uint]string) (*Enum, error) {
+	e := &Enum{
+		names: make(map[uint]string, len(names)),
+		codes: make(map[string]uint, len(names)),
+	}
+
+	for code, name := range names {
+		if _, ok := e.codes[name]; ok {
+			return nil, fmt.Errorf("enum name %q: %w", name, ErrDuplicateEnumName)
+		}
+		e.names[code] = name
+		e.codes[name] = code
+	}
+
+	return e, nil
+}
+
+// Name of the code, or false if the code is unknown
+func (e *Enum) Name(code uint) (string, bool) {
+	name, ok := e.names[code]
+	return name, ok
+}
+
+// Code of the name, or false if the name is unknown
+func (e *Enum) Code(name string) (uint, bool) {
+	code, ok := e.codes[name]
+	return code, ok
+}
+
+// Number of bits needed for the largest code
+func (e *Enum) width() int {
+	n := 0
+	for code := range e.names {
+		if l := bits.Len(code); l > n {
+			n = l
+		}
+	}
+	return n
+}
+
+// Read an enumerated value of an array, returning its name, or
+// ErrUnknownEnumCode if the code read has no name
+func ReadEnum(array []byte, mask []byte, enum *Enum) (string, error) {
+	code, err := ReadFromArrayE(array, mask)
+	if err != nil {
+		return "", err
+	}
+	name, ok := enum.Name(code)
+	if !ok {
+		return "", fmt.Errorf("code %d: %w", code, ErrUnknownEnumCode)
+	}
+	return name, nil
+}
+
+// Write an enumerated value to an array by its name, returning
+// ErrUnknownEnumName if the enum has no such name
+func WriteEnum(array []byte, mask []byte, enum *Enum, name string) ([]byte, error) {
+	code, ok := enum.Code(name)
+	if !ok {
+		return array, fmt.Errorf("name %q: %w", name, ErrUnknownEnumName)
+	}
+	return WriteToArray(array, mask, code)
+}
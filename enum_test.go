@@ -0,0 +1,78 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	focusMode, e := NewEnum(map[uint]string{2: "auto", 3: "manual"})
+	if e != nil {
+		t.Fatalf("NewEnum threw '%s'", e)
+	}
+
+	array := []byte{0x81, 0x01, 0x04, 0x38, 0x03, 0xFF}
+	mask := []byte{0x00, 0x00, 0x00, 0x00, 0x0F}
+	if got, e := ReadEnum(array, mask, focusMode); e != nil || got != "manual" {
+		t.Errorf("ReadEnum(%x, %x) = %q, '%v', want %q", array, mask, got, e, "manual")
+	}
+
+	array = []byte{0x81, 0x01, 0x04, 0x38, 0x00, 0xFF}
+	want := []byte{0x81, 0x01, 0x04, 0x38, 0x02, 0xFF}
+	if got, e := WriteEnum(array, mask, focusMode, "auto"); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteEnum(%x, %x, auto) = %x, '%v', want %x", array, mask, got, e, want)
+	}
+
+	array = []byte{0x81, 0x01, 0x04, 0x38, 0x04, 0xFF}
+	if _, e := ReadEnum(array, mask, focusMode); !errors.Is(e, ErrUnknownEnumCode) {
+		t.Errorf("ReadEnum(%x, %x) didn't throw '%s', but '%v'", array, mask, ErrUnknownEnumCode, e)
+	}
+	if _, e := WriteEnum(array, mask, focusMode, "fixed"); !errors.Is(e, ErrUnknownEnumName) {
+		t.Errorf("WriteEnum(%x, %x, fixed) didn't throw '%s', but '%v'", array, mask, ErrUnknownEnumName, e)
+	}
+
+	if _, e := NewEnum(map[uint]string{2: "auto", 3: "auto"}); !errors.Is(e, ErrDuplicateEnumName) {
+		t.Errorf("NewEnum with duplicate names didn't throw '%s', but '%v'", ErrDuplicateEnumName, e)
+	}
+}
+
+func TestLayoutEnum(t *testing.T) {
+	focusMode, _ := NewEnum(map[uint]string{2: "auto", 3: "manual"})
+
+	layout, e := NewLayout(2,
+		Field{Name: "mode", MaskTypePair: MaskTypePair{Mask: []byte{0x0F}}, Enum: focusMode, Default: "auto"},
+		Field{Name: "level", MaskTypePair: MaskTypePair{[]byte{0xFF}, reflect.Uint8, 1, 0}},
+	)
+	if e != nil {
+		t.Fatalf("NewLayout threw '%s'", e)
+	}
+
+	want := []byte{0x02, 0x10}
+	if got, e := layout.Encode(map[string]interface{}{"level": 0x10}); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Encode = %x, '%v', want %x", got, e, want)
+	}
+
+	frame := []byte{0x03, 0x10}
+	wantValues := map[string]interface{}{"mode": "manual", "level": uint8(0x10)}
+	if got, e := layout.Decode(frame); e != nil || !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("Decode(%x) = %v, '%v', want %v", frame, got, e, wantValues)
+	}
+
+	frame = []byte{0x05, 0x10}
+	if _, e := layout.Decode(frame); !errors.Is(e, ErrUnknownEnumCode) {
+		t.Errorf("Decode(%x) didn't throw '%s', but '%v'", frame, ErrUnknownEnumCode, e)
+	}
+	if _, e := layout.Encode(map[string]interface{}{"mode": "fixed"}); !errors.Is(e, ErrUnknownEnumName) {
+		t.Errorf("Encode(mode: fixed) didn't throw '%s', but '%v'", ErrUnknownEnumName, e)
+	}
+	if _, e := layout.Encode(map[string]interface{}{"mode": 2}); !errors.Is(e, ErrInterfaceTypeNotSupported) {
+		t.Errorf("Encode(mode: 2) didn't throw '%s', but '%v'", ErrInterfaceTypeNotSupported, e)
+	}
+
+	wide, _ := NewEnum(map[uint]string{0x10: "wide"})
+	if _, e := NewLayout(1, Field{Name: "mode", MaskTypePair: MaskTypePair{Mask: []byte{0x0F}}, Enum: wide}); !errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+		t.Errorf("NewLayout with too wide enum didn't throw '%s', but '%v'", ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
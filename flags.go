@@ -0,0 +1,100 @@
+package bitbytepack
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Struct type to contain both a mask array and a boolean flag
+type MaskValuePairBool struct {
+	Mask   []byte // mask array
+	Value  bool   // flag to be embedded
+	Offset int    // index in the array of the first byte of the mask
+}
+
+// Write the flag to the array using the mask
+func (m MaskValuePairBool) write(array []byte, opts writeOptions) ([]byte, error) {
+	return MaskValue[uint8]{m.Mask, boolToUint8(m.Value), m.Offset}.write(array, opts)
+}
+
+// Offset and mask of the MaskValuePairBool
+func (m MaskValuePairBool) placement() (int, []byte) {
+	return m.Offset, m.Mask
+}
+
+// Value of the MaskValuePairBool
+func (m MaskValuePairBool) value() interface{} {
+	return m.Value
+}
+
+func boolToUint8(value bool) uint8 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Read a flag of an array, true if any of the masked bits are set
+func ReadBool(array []byte, mask []byte) bool {
+	return isSet(array, mask)
+}
+
+// Write a flag to an array, as the value 1 for true and 0 for false
+func WriteBool(array []byte, mask []byte, value bool) ([]byte, error) {
+	return WriteToArray(array, mask, uint(boolToUint8(value)))
+}
+
+// Named single-bit flags, such as the bits of a status byte
+type Flags struct {
+	names []string
+	masks map[string][]byte
+}
+
+// Create Flags from the masks of each flag, which must have exactly one bit set
+func NewFlags(masks map[string][]byte) (*Flags, error) {
+	f := &Flags{masks: make(map[string][]byte, len(masks))}
+
+	for name, m := range masks {
+		if CountOnes(m) != 1 {
+			return nil, fmt.Errorf("flag %q: %w", name, ErrNotSingleBit)
+		}
+		f.names = append(f.names, name)
+		f.masks[name] = m
+	}
+
+	return f, nil
+}
+
+// Mask of the named flag, or nil if there is no such flag
+func (f *Flags) Mask(name string) []byte {
+	return f.masks[name]
+}
+
+// Decode all the flags of array
+func (f *Flags) Decode(array []byte) map[string]bool {
+	values := make(map[string]bool, len(f.names))
+	for _, name := range f.names {
+		values[name] = ReadBool(array, f.masks[name])
+	}
+	return values
+}
+
+// Decode the flags of array into the bool fields of the struct pointed to by
+// v that have the same name as a flag. Other fields are left untouched.
+func (f *Flags) DecodeInto(array []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInterfaceTypeNotSupported
+	}
+	rv = rv.Elem()
+
+	for _, name := range f.names {
+		field := rv.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() || field.Kind() != reflect.Bool {
+			continue
+		}
+		field.SetBool(ReadBool(array, f.masks[name]))
+	}
+
+	return nil
+}
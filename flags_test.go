@@ -0,0 +1,68 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReadWriteBool(t *testing.T) {
+	array := []byte{0x00, 0x04}
+	if got := ReadBool(array, []byte{0x00, 0x04}); !got {
+		t.Errorf("ReadBool(%x, 0004) = %t, want true", array, got)
+	}
+	if got := ReadBool(array, []byte{0x80, 0x00}); got {
+		t.Errorf("ReadBool(%x, 8000) = %t, want false", array, got)
+	}
+
+	array = []byte{0x00, 0x00}
+	want := []byte{0x00, 0x10}
+	if got, e := WriteBool(array, []byte{0x00, 0x10}, true); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteBool(%x, 0010, true) = %x, want %x", array, got, want)
+	}
+
+	array = []byte{0xFF, 0x00}
+	maskValuePairs := []interface{}{
+		MaskValuePairBool{[]byte{0x80}, false, 0},
+		MaskValuePairBool{[]byte{0x01}, true, 1},
+		MaskValuePair8{[]byte{0x0F}, 0x2, 0},
+	}
+	want = []byte{0x72, 0x01}
+	if got, e := MultReplaceInArray(array, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultReplaceInArray(%x, %v) = %x, want %x", array, maskValuePairs, got, want)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	flags, e := NewFlags(map[string][]byte{
+		"Power":  Bits(0, 7, 7),
+		"Error":  Bits(0, 0, 0),
+		"Moving": Bits(1, 3, 3),
+	})
+	if e != nil {
+		t.Fatalf("NewFlags threw '%s'", e)
+	}
+
+	array := []byte{0x81, 0x00}
+	want := map[string]bool{"Power": true, "Error": true, "Moving": false}
+	if got := flags.Decode(array); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flags.Decode(%x) = %v, want %v", array, got, want)
+	}
+
+	status := struct {
+		Power  bool
+		Moving bool
+		Zoom   uint8
+	}{Moving: true, Zoom: 3}
+	if e := flags.DecodeInto(array, &status); e != nil || !status.Power || status.Moving || status.Zoom != 3 {
+		t.Errorf("Flags.DecodeInto(%x) = %+v, '%v'", array, status, e)
+	}
+	if e := flags.DecodeInto(array, status); e != ErrInterfaceTypeNotSupported {
+		t.Errorf("Flags.DecodeInto(%x, struct) didn't throw '%s', but '%s'", array, ErrInterfaceTypeNotSupported, e)
+	}
+
+	if _, e := NewFlags(map[string][]byte{"Mode": {0x03}}); !errors.Is(e, ErrNotSingleBit) {
+		t.Errorf("NewFlags with two bits didn't throw '%s', but '%s'", ErrNotSingleBit, e)
+	}
+}
@@ -0,0 +1,164 @@
+package bitbytepack
+
+import "math"
+
+// Encoding of the value of a MaskTypePair that has no reflect.Kind
+type Format int
+
+const (
+	FormatKind     Format = iota // encoded as given by the Type of the MaskTypePair
+	FormatFloat16                // IEEE 754 half precision, read as float32
+	FormatBFloat16               // bfloat16, the top half of a float32, read as float32
+)
+
+// Struct type to contain both a mask array and a value to embed as float16
+type MaskValuePair16F struct {
+	Mask   []byte  // mask array
+	Value  float32 // value to be embedded as float16
+	Offset int     // index in the array of the first byte of the mask
+}
+
+// Write the value to the array using the mask
+func (m MaskValuePair16F) write(array []byte, opts writeOptions) ([]byte, error) {
+	return writeFloat16(array, m.Mask, m.Offset, float32ToFloat16(m.Value), opts)
+}
+
+// Offset and mask of the MaskValuePair16F
+func (m MaskValuePair16F) placement() (int, []byte) {
+	return m.Offset, m.Mask
+}
+
+// Value of the MaskValuePair16F
+func (m MaskValuePair16F) value() interface{} {
+	return m.Value
+}
+
+// Struct type to contain both a mask array and a value to embed as bfloat16
+type MaskValuePair16BF struct {
+	Mask   []byte  // mask array
+	Value  float32 // value to be embedded as bfloat16
+	Offset int     // index in the array of the first byte of the mask
+}
+
+// Write the value to the array using the mask
+func (m MaskValuePair16BF) write(array []byte, opts writeOptions) ([]byte, error) {
+	return writeFloat16(array, m.Mask, m.Offset, float32ToBFloat16(m.Value), opts)
+}
+
+// Offset and mask of the MaskValuePair16BF
+func (m MaskValuePair16BF) placement() (int, []byte) {
+	return m.Offset, m.Mask
+}
+
+// Value of the MaskValuePair16BF
+func (m MaskValuePair16BF) value() interface{} {
+	return m.Value
+}
+
+// Write the bits of a 16-bit float, which require at least 16 bits in the mask
+func writeFloat16(array []byte, mask []byte, offset int, value uint16, opts writeOptions) ([]byte, error) {
+	if CountOnes(mask) < 16 {
+		return array, ErrNotEnoughBitsToEmbedValue
+	}
+	return MaskValue[uint16]{mask, value, offset}.write(array, opts)
+}
+
+// Overload to read embedded float16 values of []byte array
+func ReadFromArray16F(array []byte, mask []byte) float32 {
+	return float16ToFloat32(ReadFromArray16(array, mask))
+}
+
+// "Overload" to embed a value as float16 in a []byte array, rounded to the
+// nearest float16
+func WriteToArray16F(array []byte, mask []byte, value float32) ([]byte, error) {
+	return MaskValuePair16F{mask, value, 0}.write(array, writeOptions{})
+}
+
+// Overload to read embedded bfloat16 values of []byte array
+func ReadFromArray16BF(array []byte, mask []byte) float32 {
+	return math.Float32frombits(uint32(ReadFromArray16(array, mask)) << 16)
+}
+
+// "Overload" to embed a value as bfloat16 in a []byte array, rounded to the
+// nearest bfloat16
+func WriteToArray16BF(array []byte, mask []byte, value float32) ([]byte, error) {
+	return MaskValuePair16BF{mask, value, 0}.write(array, writeOptions{})
+}
+
+// Convert a float32 to the bits of the nearest float16, rounding ties to even
+func float32ToFloat16(value float32) uint16 {
+	b := math.Float32bits(value)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xFF
+	mant := b & 0x7FFFFF
+
+	// Infinity and NaN, keeping NaN quiet
+	if exp == 0xFF {
+		if mant != 0 {
+			return sign | 0x7E00 | uint16(mant>>13)
+		}
+		return sign | 0x7C00
+	}
+
+	// Rebias the exponent
+	e := exp - 127 + 15
+
+	// Too large, round to infinity
+	if e >= 0x1F {
+		return sign | 0x7C00
+	}
+
+	// Subnormal or zero
+	if e <= 0 {
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := mant >> shift
+		rem, half := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > half || (rem == half && h&1 == 1) {
+			h++
+		}
+		return sign | uint16(h)
+	}
+
+	// Normal, where rounding up may carry into the exponent and up to infinity
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1FFF
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		h++
+	}
+	return sign | uint16(h)
+}
+
+// Convert the bits of a float16 to a float32, which is exact
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0:
+		// Subnormal or zero
+		f := float32(mant) * (1.0 / (1 << 24))
+		return math.Float32frombits(math.Float32bits(f) | sign)
+	case 0x1F:
+		// Infinity and NaN
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// Convert a float32 to the bits of the nearest bfloat16, rounding ties to even
+func float32ToBFloat16(value float32) uint16 {
+	b := math.Float32bits(value)
+
+	// Keep NaN quiet, as rounding could turn it into infinity
+	if value != value {
+		return uint16(b>>16) | 0x40
+	}
+
+	b += 0x7FFF + (b>>16)&1
+	return uint16(b >> 16)
+}
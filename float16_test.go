@@ -0,0 +1,122 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFloat16(t *testing.T) {
+	tests := []struct {
+		value float32
+		bits  uint16
+		exact bool // value converts back exactly
+	}{
+		{1, 0x3C00, true},
+		{-2, 0xC000, true},
+		{0.1, 0x2E66, false},
+		{65504, 0x7BFF, true},
+		{65520, 0x7C00, false},                           // rounds up to infinity
+		{float32(math.Inf(-1)), 0xFC00, true},            // infinity
+		{float32(math.Ldexp(1, -14)), 0x0400, true},      // smallest normal
+		{float32(math.Ldexp(1023, -24)), 0x03FF, true},   // largest subnormal
+		{float32(math.Ldexp(1, -24)), 0x0001, true},      // smallest subnormal
+		{float32(math.Ldexp(1, -25)), 0x0000, false},     // tie, rounds to even
+		{float32(math.Ldexp(3, -26)), 0x0001, false},     // above tie
+		{float32(math.Ldexp(3, -25)), 0x0002, false},     // tie, rounds to even
+		{1 + float32(math.Ldexp(1, -11)), 0x3C00, false}, // tie, rounds to even
+		{1 + float32(math.Ldexp(3, -11)), 0x3C02, false}, // tie, rounds to even
+		{float32(math.Copysign(0, -1)), 0x8000, true},    // negative zero
+	}
+
+	mask := []byte{0x00, 0xFF, 0xFF}
+	for _, tt := range tests {
+		if got := float32ToFloat16(tt.value); got != tt.bits {
+			t.Errorf("float32ToFloat16(%g) = %04x, want %04x", tt.value, got, tt.bits)
+		}
+
+		array, e := WriteToArray16F(make([]byte, 3), mask, tt.value)
+		want := []byte{0x00, byte(tt.bits >> 8), byte(tt.bits)}
+		if e != nil || !bytes.Equal(array, want) {
+			t.Errorf("WriteToArray16F(%x, %g) = %x, '%v', want %x", mask, tt.value, array, e, want)
+		}
+		if got := ReadFromArray16F(want, mask); tt.exact && math.Float32bits(got) != math.Float32bits(tt.value) {
+			t.Errorf("ReadFromArray16F(%x, %x) = %g, want %g", want, mask, got, tt.value)
+		}
+	}
+
+	nan := float32ToFloat16(float32(math.NaN()))
+	if nan&0x7C00 != 0x7C00 || nan&0x03FF == 0 {
+		t.Errorf("float32ToFloat16(NaN) = %04x, which is not NaN", nan)
+	}
+	if got := float16ToFloat32(0x7E00); got == got {
+		t.Errorf("float16ToFloat32(7e00) = %g, want NaN", got)
+	}
+
+	if _, e := WriteToArray16F(make([]byte, 2), []byte{0xFF}, 1); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteToArray16F with 8 bit mask didn't throw '%s', but '%v'", ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
+
+func TestBFloat16(t *testing.T) {
+	tests := []struct {
+		value float32
+		bits  uint16
+	}{
+		{1, 0x3F80},
+		{-2, 0xC000},
+		{3.14159265, 0x4049},
+		{1 + float32(math.Ldexp(1, -8)), 0x3F80}, // tie, rounds to even
+		{1 + float32(math.Ldexp(3, -8)), 0x3F82}, // tie, rounds to even
+		{math.MaxFloat32, 0x7F80},                // rounds up to infinity
+		{float32(math.Ldexp(1, -133)), 0x0001},   // subnormal
+	}
+
+	mask := []byte{0xFF, 0xFF}
+	for _, tt := range tests {
+		want := []byte{byte(tt.bits >> 8), byte(tt.bits)}
+		if got, e := WriteToArray16BF(make([]byte, 2), mask, tt.value); e != nil || !bytes.Equal(got, want) {
+			t.Errorf("WriteToArray16BF(%x, %g) = %x, '%v', want %x", mask, tt.value, got, e, want)
+		}
+		if got := ReadFromArray16BF(want, mask); float32ToBFloat16(got) != tt.bits {
+			t.Errorf("ReadFromArray16BF(%x, %x) = %g", want, mask, got)
+		}
+	}
+
+	array, _ := WriteToArray16BF(make([]byte, 2), mask, float32(math.NaN()))
+	if got := ReadFromArray16BF(array, mask); got == got {
+		t.Errorf("bfloat16 NaN read back as %g", got)
+	}
+}
+
+func TestMultFloat16(t *testing.T) {
+	array := make([]byte, 4)
+	maskValuePairs := []interface{}{
+		MaskValuePair16F{[]byte{0xFF, 0xFF}, 1, 0},
+		MaskValuePair16BF{[]byte{0xFF, 0xFF}, 1, 2},
+	}
+	want := []byte{0x3C, 0x00, 0x3F, 0x80}
+	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultWriteToArray(%x, %v) = %x, '%v', want %x", array, maskValuePairs, got, e, want)
+	}
+
+	masks := []MaskTypePair{
+		{[]byte{0xFF, 0xFF}, reflect.Float32, 0, FormatFloat16},
+		{[]byte{0xFF, 0xFF}, reflect.Float32, 2, FormatBFloat16}}
+	wantValues := []interface{}{float32(1), float32(1)}
+	if got, e := MultReadFromArrayE(want, masks...); e != nil || !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("MultReadFromArrayE(%x, %v) = %v, '%v', want %v", want, masks, got, e, wantValues)
+	}
+
+	layout, e := NewLayout(4,
+		Field{Name: "half", MaskTypePair: masks[0]},
+		Field{Name: "brain", MaskTypePair: masks[1]})
+	if e != nil {
+		t.Fatalf("NewLayout threw '%s'", e)
+	}
+	values := map[string]interface{}{"half": 1, "brain": 1.0}
+	if got, e := layout.Encode(values); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Encode(%v) = %x, '%v', want %x", values, got, e, want)
+	}
+}
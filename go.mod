@@ -1,3 +1,3 @@
 module github.com/pjnr1/bitbytepack
 
-go 1.15
+go 1.20
@@ -0,0 +1,188 @@
+package bitbytepack
+
+import "fmt"
+
+// Named field of a Layout
+type Field struct {
+	Name string // name used as key when encoding and decoding
+	MaskTypePair
+	Default interface{} // value to encode when none is given, or nil to leave the bits cleared
+	Enum    *Enum       // names of the codes of an enumerated field, in place of Type, or nil
+}
+
+// Layout of a frame of fixed length, made up of named fields that don't
+// overlap. The same Layout is used to both encode and decode frames.
+type Layout struct {
+	length int
+	fields []Field
+	index  map[string]int
+}
+
+// Create a Layout of a frame with the given length in bytes. The masks of the
+// fields must fit in the frame, must not overlap and must have at most 64 bits,
+// float fields must have all the bits of their bit pattern, and the
+// defaults must fit in their masks.
+func NewLayout(length int, fields ...Field) (*Layout, error) {
+	l := &Layout{
+		length: length,
+		fields: append([]Field(nil), fields...),
+		index:  make(map[string]int, len(fields)),
+	}
+
+	// Bits used by the fields so far
+	used := make([]byte, length)
+
+	// Frame to check the defaults in
+	scratch := make([]byte, length)
+
+	for i, f := range fields {
+		// Keep the mask from changing after it is validated
+		l.fields[i].Mask = clone(f.Mask)
+
+		if _, ok := l.index[f.Name]; ok {
+			return nil, fmt.Errorf("field %q: %w", f.Name, ErrDuplicateFieldName)
+		}
+		l.index[f.Name] = i
+
+		if f.Offset < 0 || f.Offset+len(f.Mask) > length {
+			return nil, fmt.Errorf("field %q: %w", f.Name, ErrMaskExceedsFrame)
+		}
+
+		// Check the kind is supported, or that the codes of an enum fit
+		if f.Enum != nil {
+			if f.Enum.width() > CountOnes(f.Mask) {
+				return nil, fmt.Errorf("field %q: %w", f.Name, ErrNotEnoughBitsToEmbedValue)
+			}
+		} else if _, ok := f.MaskTypePair.read(nil); !ok {
+			return nil, fmt.Errorf("field %q: %w", f.Name, ErrInterfaceTypeNotSupported)
+		} else if CountOnes(f.Mask) > 64 {
+			return nil, fmt.Errorf("field %q: %w", f.Name, ErrMaskTooWide)
+		} else if CountOnes(f.Mask) < f.minWidth() {
+			return nil, fmt.Errorf("field %q: %w", f.Name, ErrNotEnoughBitsToEmbedValue)
+		}
+
+		for j, m := range f.Mask {
+			if used[f.Offset+j]&m != 0 {
+				return nil, fmt.Errorf("field %q: %w", f.Name, ErrMasksOverlap)
+			}
+			used[f.Offset+j] |= m
+		}
+
+		if f.Default != nil {
+			if err := f.write(scratch, f.Default); err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// Write value to the field of the frame
+func (f Field) write(frame []byte, value interface{}) error {
+	if f.Enum != nil {
+		return f.writeEnum(frame, value)
+	}
+
+	w, err := f.MaskTypePair.with(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.write(frame, writeOptions{replace: true})
+	return err
+}
+
+// Write the name of an enumerated value to the field of the frame
+func (f Field) writeEnum(frame []byte, value interface{}) error {
+	name, ok := value.(string)
+	if !ok {
+		return ErrInterfaceTypeNotSupported
+	}
+	code, ok := f.Enum.Code(name)
+	if !ok {
+		return fmt.Errorf("name %q: %w", name, ErrUnknownEnumName)
+	}
+	_, err := MaskValue[uint]{f.Mask, code, f.Offset}.write(frame, writeOptions{replace: true})
+	return err
+}
+
+// Read the field of the frame
+func (f Field) read(frame []byte) (interface{}, error) {
+	if f.Enum != nil {
+		return ReadEnum(frame[f.Offset:], f.Mask, f.Enum)
+	}
+	sub := frame[f.Offset:]
+	if err := checkRead(sub, f.Mask); err != nil {
+		return nil, err
+	}
+	value, ok := f.MaskTypePair.read(sub)
+	if !ok {
+		return nil, ErrInterfaceTypeNotSupported
+	}
+	return value, nil
+}
+
+// Length of the frame in bytes
+func (l *Layout) Len() int {
+	return l.length
+}
+
+// Fields of the layout, in the order they were given. The fields are a copy,
+// so changing them doesn't change the layout.
+func (l *Layout) Fields() []Field {
+	fields := append([]Field(nil), l.fields...)
+	for i := range fields {
+		fields[i].Mask = clone(fields[i].Mask)
+	}
+	return fields
+}
+
+// Encode values into a new frame. Fields without a value are set to their
+// default. Values may be of any number type, as long as they can be
+// represented by the kind of the field. Values of enumerated fields are
+// given by name. A value that can't be written is returned as a *FieldError.
+func (l *Layout) Encode(values map[string]interface{}) ([]byte, error) {
+	for name := range values {
+		if _, ok := l.index[name]; !ok {
+			return nil, fmt.Errorf("field %q: %w", name, ErrUnknownField)
+		}
+	}
+
+	frame := make([]byte, l.length)
+
+	for i, f := range l.fields {
+		value, ok := values[f.Name]
+		if !ok {
+			value = f.Default
+		}
+		if value == nil {
+			continue
+		}
+
+		if err := f.write(frame, value); err != nil {
+			return nil, &FieldError{Index: i, Name: f.Name, Mask: f.Mask, Value: value, Err: err}
+		}
+	}
+
+	return frame, nil
+}
+
+// Decode all the fields of frame. The values have the type given by the kind
+// of each field, and enumerated fields are decoded to their name.
+func (l *Layout) Decode(frame []byte) (map[string]interface{}, error) {
+	if len(frame) < l.length {
+		return nil, ErrArrayShorterThanMask
+	}
+
+	values := make(map[string]interface{}, len(l.fields))
+
+	for _, f := range l.fields {
+		value, err := f.read(frame)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		values[f.Name] = value
+	}
+
+	return values, nil
+}
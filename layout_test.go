@@ -0,0 +1,134 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	layout, e := NewLayout(7,
+		Field{Name: "header", MaskTypePair: MaskTypePair{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, reflect.Uint32, 0, 0}, Default: uint32(0x81010447)},
+		Field{Name: "zoom", MaskTypePair: MaskTypePair{[]byte{0x0F, 0x0F}, reflect.Uint8, 4, 0}},
+		Field{Name: "offset", MaskTypePair: MaskTypePair{[]byte{0xF0, 0xF0}, reflect.Int8, 4, 0}, Default: -1},
+		Field{Name: "terminator", MaskTypePair: MaskTypePair{[]byte{0xFF}, reflect.Uint8, 6, 0}, Default: 0xFF},
+	)
+	if e != nil {
+		t.Fatalf("NewLayout threw '%s'", e)
+	}
+
+	values := map[string]interface{}{"zoom": 0x24}
+	want := []byte{0x81, 0x01, 0x04, 0x47, 0xF2, 0xF4, 0xFF}
+	got, e := layout.Encode(values)
+	if e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Encode(%v) = %x, '%v', want %x", values, got, e, want)
+	}
+
+	wantValues := map[string]interface{}{
+		"header":     uint32(0x81010447),
+		"zoom":       uint8(0x24),
+		"offset":     int8(-1),
+		"terminator": uint8(0xFF),
+	}
+	if got, e := layout.Decode(want); e != nil || !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("Decode(%x) = %v, '%v', want %v", want, got, e, wantValues)
+	}
+
+	// Decoded values can be encoded again
+	if got, e := layout.Encode(wantValues); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Encode(%v) = %x, '%v', want %x", wantValues, got, e, want)
+	}
+
+	if _, e := layout.Decode(want[:6]); e != ErrArrayShorterThanMask {
+		t.Errorf("Decode(%x) didn't throw '%s', but '%s'", want[:6], ErrArrayShorterThanMask, e)
+	}
+
+	encodeErrors := []struct {
+		values map[string]interface{}
+		want   error
+	}{
+		{map[string]interface{}{"focus": 1}, ErrUnknownField},
+		{map[string]interface{}{"zoom": 0x100}, ErrNotEnoughBitsToEmbedValue},
+		{map[string]interface{}{"zoom": -1}, ErrNotEnoughBitsToEmbedValue},
+		{map[string]interface{}{"zoom": 1.5}, ErrNotEnoughBitsToEmbedValue},
+		{map[string]interface{}{"zoom": "24"}, ErrInterfaceTypeNotSupported},
+	}
+	for _, tt := range encodeErrors {
+		if _, e := layout.Encode(tt.values); !errors.Is(e, tt.want) {
+			t.Errorf("Encode(%v) didn't throw '%s', but '%s'", tt.values, tt.want, e)
+		}
+	}
+
+	var fe *FieldError
+	if _, e := layout.Encode(map[string]interface{}{"zoom": 0x100}); !errors.As(e, &fe) || fe.Name != "zoom" || fe.Value != 0x100 {
+		t.Errorf("Encode(zoom: 0x100) = '%v', want a *FieldError of zoom", e)
+	}
+
+	// Changing the fields returned doesn't change the layout
+	fields := layout.Fields()
+	fields[1].Offset = 0
+	fields[1].Mask[0] = 0xFF
+	if got, e := layout.Decode(want); e != nil || got["zoom"] != uint8(0x24) {
+		t.Errorf("Decode(%x) after changing Fields() = %v, '%v', want zoom 24", want, got, e)
+	}
+}
+
+func TestNewLayoutErrors(t *testing.T) {
+	tests := []struct {
+		fields []Field
+		want   error
+	}{
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F, 0xF0}, reflect.Uint8, 0, 0}},
+			{Name: "b", MaskTypePair: MaskTypePair{[]byte{0x10}, reflect.Uint8, 1, 0}},
+		}, ErrMasksOverlap},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F}, reflect.Uint8, 0, 0}},
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0xF0}, reflect.Uint8, 0, 0}},
+		}, ErrDuplicateFieldName},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F, 0x0F}, reflect.Uint8, 1, 0}},
+		}, ErrMaskExceedsFrame},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F}, reflect.Uint8, -1, 0}},
+		}, ErrMaskExceedsFrame},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F}, reflect.String, 0, 0}},
+		}, ErrInterfaceTypeNotSupported},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0x0F}, reflect.Uint8, 0, 0}, Default: 0x10},
+		}, ErrNotEnoughBitsToEmbedValue},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0xFF}, reflect.Float32, 0, 0}},
+		}, ErrNotEnoughBitsToEmbedValue},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0xFF, 0xFF}, reflect.Float64, 0, 0}},
+		}, ErrNotEnoughBitsToEmbedValue},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0xFF}, reflect.Float32, 0, FormatFloat16}},
+		}, ErrNotEnoughBitsToEmbedValue},
+		{[]Field{
+			{Name: "a", MaskTypePair: MaskTypePair{[]byte{0xFF}, reflect.Float32, 0, FormatBFloat16}},
+		}, ErrNotEnoughBitsToEmbedValue},
+	}
+
+	for _, tt := range tests {
+		if _, e := NewLayout(2, tt.fields...); !errors.Is(e, tt.want) {
+			t.Errorf("NewLayout(2, %v) didn't throw '%s', but '%s'", tt.fields, tt.want, e)
+		}
+	}
+}
+
+func TestNewLayoutMaskTooWide(t *testing.T) {
+	mask := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	if _, e := NewLayout(9, Field{Name: "a", MaskTypePair: MaskTypePair{mask, reflect.Uint64, 0, 0}}); !errors.Is(e, ErrMaskTooWide) {
+		t.Errorf("NewLayout(9, %x) didn't throw '%s', but '%s'", mask, ErrMaskTooWide, e)
+	}
+
+	// A field made by hand, bypassing NewLayout, fails to decode instead of panicking
+	layout := &Layout{length: 9, fields: []Field{{Name: "a", MaskTypePair: MaskTypePair{mask, reflect.Uint64, 0, 0}}}}
+	if _, e := layout.Decode(make([]byte, 9)); !errors.Is(e, ErrMaskTooWide) {
+		t.Errorf("Decode() didn't throw '%s', but '%s'", ErrMaskTooWide, e)
+	}
+}
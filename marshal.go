@@ -0,0 +1,163 @@
+package bitbytepack
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Name of the struct tag used by Marshal and Unmarshal
+const tagName = "bitbytepack"
+
+// Struct layout deduced from the tags of a struct type
+type structLayout struct {
+	template []byte
+	layout   *Layout
+	index    []int // index in the struct of each field in the layout
+}
+
+// Marshal a struct into a frame, using the struct tags of its fields. Each
+// field to embed carries a tag with its mask in hex, and optionally the index
+// of the first byte of the mask in the frame:
+//
+//	Zoom uint16 `bitbytepack:"mask=0F0F0F0F,offset=4"`
+//
+// The constant bytes of the frame can be given on a blank field:
+//
+//	_ struct{} `bitbytepack:"template=81 01 04 47 00 00 00 00 FF"`
+//
+// Without a template, the frame is as long as the masks and zero elsewhere.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrInterfaceTypeNotSupported
+	}
+
+	sl, err := parseStruct(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	frame := clone(sl.template)
+
+	for i, f := range sl.layout.fields {
+		value := rv.Field(sl.index[i]).Interface()
+		if err := f.write(frame, value); err != nil {
+			return nil, &FieldError{Index: i, Name: f.Name, Mask: f.Mask, Value: value, Err: err}
+		}
+	}
+
+	return frame, nil
+}
+
+// Unmarshal a frame into the struct pointed to by v, using the struct tags of
+// its fields, see Marshal
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInterfaceTypeNotSupported
+	}
+	rv = rv.Elem()
+
+	sl, err := parseStruct(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	if len(data) < sl.layout.Len() {
+		return ErrArrayShorterThanMask
+	}
+
+	for i, f := range sl.layout.fields {
+		value, err := f.read(data)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		field := rv.Field(sl.index[i])
+		field.Set(reflect.ValueOf(value).Convert(field.Type()))
+	}
+
+	return nil
+}
+
+// Deduce the layout of a struct type from its tags
+func parseStruct(t reflect.Type) (*structLayout, error) {
+	sl := &structLayout{}
+	var fields []Field
+	length := 0
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+
+		f := Field{Name: sf.Name, MaskTypePair: MaskTypePair{Type: sf.Type.Kind()}}
+		for _, option := range strings.Split(tag, ",") {
+			key, value, err := splitOption(option)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+			}
+
+			switch key {
+			case "mask":
+				f.Mask, err = parseHex(value)
+			case "offset":
+				f.Offset, err = strconv.Atoi(value)
+			case "template":
+				sl.template, err = parseHex(value)
+			default:
+				err = ErrInvalidTag
+			}
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", sf.Name, ErrInvalidTag)
+			}
+		}
+
+		// Fields only carrying the template
+		if f.Mask == nil {
+			continue
+		}
+		if sf.PkgPath != "" {
+			return nil, fmt.Errorf("field %s: %w", sf.Name, ErrInvalidTag)
+		}
+
+		fields = append(fields, f)
+		sl.index = append(sl.index, i)
+		if n := f.Offset + len(f.Mask); n > length {
+			length = n
+		}
+	}
+
+	if sl.template != nil {
+		length = len(sl.template)
+	} else {
+		sl.template = make([]byte, length)
+	}
+
+	var err error
+	if sl.layout, err = NewLayout(length, fields...); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// Split a "key=value" option of a tag
+func splitOption(option string) (string, string, error) {
+	i := strings.IndexByte(option, '=')
+	if i < 0 {
+		return "", "", ErrInvalidTag
+	}
+	return strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:]), nil
+}
+
+// Parse hex bytes, optionally separated by spaces
+func parseHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+}
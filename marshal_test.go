@@ -0,0 +1,116 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type focusMode uint8
+
+type zoomCommand struct {
+	_      struct{}  `bitbytepack:"template=81 01 04 47 00 00 00 00 FF"`
+	Zoom   uint16    `bitbytepack:"mask=0F0F0F0F,offset=4"`
+	Mode   focusMode `bitbytepack:"mask=F0,offset=4"`
+	Offset int8      `bitbytepack:"mask=F0F0,offset=5"`
+	Note   string
+}
+
+func TestMarshal(t *testing.T) {
+	cmd := zoomCommand{Zoom: 0x1234, Mode: 2, Offset: -2, Note: "not embedded"}
+	want := []byte{0x81, 0x01, 0x04, 0x47, 0x21, 0xF2, 0xE3, 0x04, 0xFF}
+
+	if got, e := Marshal(cmd); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Marshal(%+v) = %x, '%v', want %x", cmd, got, e, want)
+	}
+	if got, e := Marshal(&cmd); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Marshal(&%+v) = %x, '%v', want %x", cmd, got, e, want)
+	}
+
+	var got zoomCommand
+	if e := Unmarshal(want, &got); e != nil || got.Zoom != cmd.Zoom || got.Mode != cmd.Mode || got.Offset != cmd.Offset {
+		t.Errorf("Unmarshal(%x) = %+v, '%v', want %+v", want, got, e, cmd)
+	}
+
+	if e := Unmarshal(want[:8], &got); e != ErrArrayShorterThanMask {
+		t.Errorf("Unmarshal(%x) didn't throw '%s', but '%s'", want[:8], ErrArrayShorterThanMask, e)
+	}
+	if e := Unmarshal(want, got); e != ErrInterfaceTypeNotSupported {
+		t.Errorf("Unmarshal(%x, struct) didn't throw '%s', but '%s'", want, ErrInterfaceTypeNotSupported, e)
+	}
+
+	cmd.Mode = 0x10
+	if _, e := Marshal(cmd); !errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+		t.Errorf("Marshal(%+v) didn't throw '%s', but '%s'", cmd, ErrNotEnoughBitsToEmbedValue, e)
+	}
+	var fe *FieldError
+	if _, e := Marshal(cmd); !errors.As(e, &fe) || fe.Name != "Mode" {
+		t.Errorf("Marshal(%+v) = '%v', want a *FieldError of Mode", cmd, e)
+	}
+}
+
+func TestMarshalWithoutTemplate(t *testing.T) {
+	type flags struct {
+		A uint8 `bitbytepack:"mask=0F0F"`
+		B uint8 `bitbytepack:"mask=FF,offset=2"`
+		C bool  `bitbytepack:"mask=80"`
+	}
+	v := flags{0x12, 0x34, true}
+	want := []byte{0x81, 0x02, 0x34}
+
+	if got, e := Marshal(v); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("Marshal(%+v) = %x, '%v', want %x", v, got, e, want)
+	}
+
+	var got flags
+	if e := Unmarshal(want, &got); e != nil || got != v {
+		t.Errorf("Unmarshal(%x) = %+v, '%v', want %+v", want, got, e, v)
+	}
+}
+
+func TestMarshalTagErrors(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want error
+	}{
+		{struct {
+			A uint8 `bitbytepack:"mask=0G"`
+		}{}, ErrInvalidTag},
+		{struct {
+			A uint8 `bitbytepack:"mask"`
+		}{}, ErrInvalidTag},
+		{struct {
+			A uint8 `bitbytepack:"mask=0F,size=2"`
+		}{}, ErrInvalidTag},
+		{struct {
+			a uint8 `bitbytepack:"mask=0F"`
+		}{}, ErrInvalidTag},
+		{struct {
+			A uint8 `bitbytepack:"mask=0F"`
+			B uint8 `bitbytepack:"mask=08"`
+		}{}, ErrMasksOverlap},
+		{struct {
+			_ struct{} `bitbytepack:"template=0000"`
+			A uint8    `bitbytepack:"mask=0F,offset=2"`
+		}{}, ErrMaskExceedsFrame},
+		{struct {
+			A string `bitbytepack:"mask=0F"`
+		}{}, ErrInterfaceTypeNotSupported},
+	}
+
+	for _, tt := range tests {
+		if _, e := Marshal(tt.v); !errors.Is(e, tt.want) {
+			t.Errorf("Marshal(%+v) didn't throw '%s', but '%s'", tt.v, tt.want, e)
+		}
+	}
+}
+
+func TestUnmarshalMaskTooWide(t *testing.T) {
+	var v struct {
+		A uint64 `bitbytepack:"mask=FFFFFFFFFFFFFFFFFF"`
+	}
+	data := make([]byte, 9)
+	if e := Unmarshal(data, &v); !errors.Is(e, ErrMaskTooWide) {
+		t.Errorf("Unmarshal(%x) didn't throw '%s', but '%s'", data, ErrMaskTooWide, e)
+	}
+}
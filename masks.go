@@ -0,0 +1,176 @@
+package bitbytepack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Largest byte index accepted in a mask spec, so a spec from an untrusted
+// source can't make ParseMask allocate a huge mask
+const maxSpecByteIndex = 1<<16 - 1
+
+// Half of a byte
+type Half int
+
+const (
+	Low  Half = iota // bits 3 to 0
+	High             // bits 7 to 4
+)
+
+// Mask with bits hiBit down to loBit set in byte byteIndex, where bit 7 is
+// the most significant bit. Panics if the bits are out of order or range.
+func Bits(byteIndex int, hiBit int, loBit int) []byte {
+	if byteIndex < 0 || loBit < 0 || hiBit > 7 || hiBit < loBit {
+		panic(fmt.Sprintf("bitbytepack: invalid bits %d[%d:%d]", byteIndex, hiBit, loBit))
+	}
+	mask := make([]byte, byteIndex+1)
+	mask[byteIndex] = byte(0xFF<<uint(loBit)) & byte(0xFF>>uint(7-hiBit))
+	return mask
+}
+
+// Mask with the given half of every byte from first to last set
+func Nibbles(first int, last int, half Half) []byte {
+	if first < 0 || last < first {
+		panic(fmt.Sprintf("bitbytepack: invalid nibbles %d to %d", first, last))
+	}
+	nibble := byte(0x0F)
+	if half == High {
+		nibble = 0xF0
+	}
+	mask := make([]byte, last+1)
+	for i := first; i <= last; i++ {
+		mask[i] = nibble
+	}
+	return mask
+}
+
+// Mask with length consecutive bits set, starting at bit startBit counted
+// from the most significant bit of the first byte
+func Range(startBit int, length int) []byte {
+	if startBit < 0 || length < 0 {
+		panic(fmt.Sprintf("bitbytepack: invalid range %d+%d", startBit, length))
+	}
+	end := startBit + length
+	mask := make([]byte, (end+7)/8)
+	for b := startBit; b < end; b++ {
+		mask[b/8] |= 0x80 >> uint(b%8)
+	}
+	return mask
+}
+
+// Mask with the bits of all the masks set
+func Union(masks ...[]byte) []byte {
+	n := 0
+	for _, m := range masks {
+		if len(m) > n {
+			n = len(m)
+		}
+	}
+	mask := make([]byte, n)
+	for _, m := range masks {
+		for i, b := range m {
+			mask[i] |= b
+		}
+	}
+	return mask
+}
+
+// Parse a textual mask spec, made of comma separated terms. Each term is a
+// byte index, optionally followed by a bit or a range of bits in brackets:
+//
+//	"4[3:0],5[3:0]"  low nibbles of bytes 4 and 5
+//	"2,3[7]"         all of byte 2 and the top bit of byte 3
+//
+// Byte indices above 65535 are rejected with ErrInvalidMaskSpec.
+func ParseMask(spec string) ([]byte, error) {
+	var masks [][]byte
+	for _, term := range strings.Split(spec, ",") {
+		m, err := parseTerm(strings.TrimSpace(term))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", term, err)
+		}
+		masks = append(masks, m)
+	}
+	return Union(masks...), nil
+}
+
+// Parse a single term of a mask spec
+func parseTerm(term string) ([]byte, error) {
+	index, bitSpec, hasBits := strings.Cut(term, "[")
+	byteIndex, err := strconv.Atoi(index)
+	if err != nil || byteIndex < 0 || byteIndex > maxSpecByteIndex {
+		return nil, ErrInvalidMaskSpec
+	}
+	if !hasBits {
+		return Bits(byteIndex, 7, 0), nil
+	}
+
+	bitSpec, ok := strings.CutSuffix(bitSpec, "]")
+	if !ok {
+		return nil, ErrInvalidMaskSpec
+	}
+	hi, lo, isRange := strings.Cut(bitSpec, ":")
+	if !isRange {
+		lo = hi
+	}
+
+	hiBit, errHi := strconv.Atoi(hi)
+	loBit, errLo := strconv.Atoi(lo)
+	if errHi != nil || errLo != nil || loBit < 0 || hiBit > 7 || hiBit < loBit {
+		return nil, ErrInvalidMaskSpec
+	}
+	return Bits(byteIndex, hiBit, loBit), nil
+}
+
+// Parse a command template of hex bytes, with placeholder letters in place of
+// the nibbles of the values to embed:
+//
+//	"81 01 04 47 0p 0q 0r 0s FF"
+//
+// Bytes are two digits separated by spaces, and may be prefixed by 0x. The
+// letters a to f, in either case, are hex digits, so only the other letters
+// can be placeholders. Returns the constant bytes, with the placeholder
+// nibbles cleared, and a mask as long as the template for each placeholder
+// letter, covering all its nibbles.
+func ParseTemplate(spec string) ([]byte, map[rune][]byte, error) {
+	var digits []rune
+	for _, token := range strings.Fields(spec) {
+		// Strip the prefix only from tokens like 0x12, keeping the placeholder x of 0x
+		byteDigits := []rune(token)
+		if len(byteDigits) == 4 && (strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X")) {
+			byteDigits = byteDigits[2:]
+		}
+		if len(byteDigits) != 2 {
+			return nil, nil, fmt.Errorf("%q: %w", token, ErrInvalidTemplate)
+		}
+		digits = append(digits, byteDigits...)
+	}
+	if len(digits) == 0 {
+		return nil, nil, fmt.Errorf("%q: %w", spec, ErrInvalidTemplate)
+	}
+
+	template := make([]byte, len(digits)/2)
+	masks := make(map[rune][]byte)
+
+	for i, d := range digits {
+		// Shift of the nibble in its byte
+		shift := uint(4 * (1 - i%2))
+
+		if n, err := strconv.ParseUint(string(d), 16, 4); err == nil {
+			template[i/2] |= byte(n) << shift
+			continue
+		}
+		if !unicode.IsLetter(d) {
+			return nil, nil, fmt.Errorf("%q: %w", d, ErrInvalidTemplate)
+		}
+
+		if masks[d] == nil {
+			masks[d] = make([]byte, len(template))
+		}
+		masks[d][i/2] |= 0x0F << shift
+	}
+
+	return template, masks, nil
+}
@@ -0,0 +1,120 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMaskBuilders(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{"Bits(4, 3, 0)", Bits(4, 3, 0), []byte{0x00, 0x00, 0x00, 0x00, 0x0F}},
+		{"Bits(0, 5, 2)", Bits(0, 5, 2), []byte{0x3C}},
+		{"Bits(1, 7, 7)", Bits(1, 7, 7), []byte{0x00, 0x80}},
+		{"Nibbles(4, 5, Low)", Nibbles(4, 5, Low), []byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F}},
+		{"Nibbles(0, 1, High)", Nibbles(0, 1, High), []byte{0xF0, 0xF0}},
+		{"Range(4, 8)", Range(4, 8), []byte{0x0F, 0xF0}},
+		{"Range(10, 3)", Range(10, 3), []byte{0x00, 0x38}},
+		{"Union(Bits(0, 7, 4), Bits(2, 3, 0))", Union(Bits(0, 7, 4), Bits(2, 3, 0)), []byte{0xF0, 0x00, 0x0F}},
+	}
+
+	for _, tt := range tests {
+		if !bytes.Equal(tt.got, tt.want) {
+			t.Errorf("%s = %x, want %x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestMaskBuildersPanic(t *testing.T) {
+	for _, f := range []func(){
+		func() { Bits(0, 8, 0) },
+		func() { Bits(0, 2, 3) },
+		func() { Bits(-1, 7, 0) },
+		func() { Nibbles(5, 4, Low) },
+		func() { Range(-1, 4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("invalid mask didn't panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestParseMask(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []byte
+	}{
+		{"4[3:0],5[3:0]", []byte{0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F}},
+		{"0[7:4], 0[3]", []byte{0xF8}},
+		{"2,3[7]", []byte{0x00, 0x00, 0xFF, 0x80}},
+	}
+
+	for _, tt := range tests {
+		if got, e := ParseMask(tt.spec); e != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("ParseMask(%q) = %x, '%v', want %x", tt.spec, got, e, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "a", "-1", "4[3:0", "4[0:3]", "4[8]", "4[x]", "4[3:0],", "65536", "100000000[0]", "99999999999999999999"} {
+		if _, e := ParseMask(spec); !errors.Is(e, ErrInvalidMaskSpec) {
+			t.Errorf("ParseMask(%q) didn't throw '%s', but '%v'", spec, ErrInvalidMaskSpec, e)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		spec     string
+		template []byte
+		masks    map[rune][]byte
+	}{
+		{"81 01 04 02 0z 0z FF", []byte{0x81, 0x01, 0x04, 0x02, 0x00, 0x00, 0xFF}, map[rune][]byte{
+			'z': {0x00, 0x00, 0x00, 0x00, 0x0F, 0x0F, 0x00},
+		}},
+		{"0x81 0x01 0x04 0x47 0x0p 0x0q 0x0r 0x0s 0xFF", []byte{0x81, 0x01, 0x04, 0x47, 0x00, 0x00, 0x00, 0x00, 0xFF}, map[rune][]byte{
+			'p': {0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00},
+			'q': {0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x00, 0x00},
+			'r': {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x00},
+			's': {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00},
+		}},
+		{"8x 0y 0y 0y 0y ff", []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0xFF}, map[rune][]byte{
+			'x': {0x0F, 0x00, 0x00, 0x00, 0x00, 0x00},
+			'y': {0x00, 0x0F, 0x0F, 0x0F, 0x0F, 0x00},
+		}},
+		{"81 01 04 0x FF", []byte{0x81, 0x01, 0x04, 0x00, 0xFF}, map[rune][]byte{
+			'x': {0x00, 0x00, 0x00, 0x0F, 0x00},
+		}},
+		{"pq", []byte{0x00}, map[rune][]byte{'p': {0xF0}, 'q': {0x0F}}},
+		{"81 0a FF", []byte{0x81, 0x0A, 0xFF}, map[rune][]byte{}},
+	}
+
+	for _, tt := range tests {
+		template, masks, e := ParseTemplate(tt.spec)
+		if e != nil || !bytes.Equal(template, tt.template) || !reflect.DeepEqual(masks, tt.masks) {
+			t.Errorf("ParseTemplate(%q) = %x, %x, '%v', want %x, %x", tt.spec, template, masks, e, tt.template, tt.masks)
+		}
+	}
+
+	// Filling in a parsed template
+	template, masks, _ := ParseTemplate("81 01 04 02 0z 0z FF")
+	want := []byte{0x81, 0x01, 0x04, 0x02, 0x02, 0x04, 0xFF}
+	if got, e := WriteToArray(template, masks['z'], 0x24); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteToArray(%x, %x, 24) = %x, '%v', want %x", template, masks['z'], got, e, want)
+	}
+
+	for _, spec := range []string{"", "81 0", "81 0-", "81 0_ FF", "81 1 041 FF", "8101 0604 FF", "0x8 FF"} {
+		if _, _, e := ParseTemplate(spec); !errors.Is(e, ErrInvalidTemplate) {
+			t.Errorf("ParseTemplate(%q) didn't throw '%s', but '%v'", spec, ErrInvalidTemplate, e)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package bitbytepack
+
+// Like MultReadE, but reads the values into dst instead of allocating a new
+// slice. Returns ErrDestinationTooShort if dst has fewer elements than there
+// are masks.
+func MultReadInto[T Number](array []byte, dst []T, mask ...[]byte) error {
+	return multReadInto(array, dst, noLimit, mask)
+}
+
+// Like MultReadInto, but returns ErrTooManyValues if there are more than
+// limit masks, for decoding layouts received from untrusted sources
+func MultReadIntoLimit[T Number](array []byte, dst []T, limit int, mask ...[]byte) error {
+	return multReadInto(array, dst, limit, mask)
+}
+
+func multReadInto[T Number](array []byte, dst []T, limit int, mask [][]byte) error {
+	if err := checkCount(len(mask), limit); err != nil {
+		return err
+	}
+	if len(dst) < len(mask) {
+		return ErrDestinationTooShort
+	}
+
+	for i, m := range mask {
+		if err := checkRead(array, m); err != nil {
+			return err
+		}
+		dst[i] = Read[T](array, m)
+	}
+
+	return nil
+}
+
+// Read multiple values into the pointers in dst, each value read with the
+// type pointed to. Nothing is allocated, so dst can be set up once and reused
+// for every frame. Returns ErrInterfaceTypeNotSupported if an element of dst
+// is not a pointer to a supported type.
+func MultReadFromArrayInto(array []byte, dst []interface{}, mask ...[]byte) error {
+	return multReadFromArrayInto(array, dst, noLimit, mask)
+}
+
+// Like MultReadFromArrayInto, but returns ErrTooManyValues if there are more
+// than limit masks, for decoding layouts received from untrusted sources
+func MultReadFromArrayIntoLimit(array []byte, dst []interface{}, limit int, mask ...[]byte) error {
+	return multReadFromArrayInto(array, dst, limit, mask)
+}
+
+func multReadFromArrayInto(array []byte, dst []interface{}, limit int, mask [][]byte) error {
+	if err := checkCount(len(mask), limit); err != nil {
+		return err
+	}
+	if len(dst) < len(mask) {
+		return ErrDestinationTooShort
+	}
+
+	for i, m := range mask {
+		if err := checkRead(array, m); err != nil {
+			return err
+		}
+		if err := readInto(array, m, dst[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read a value into the pointer dst
+func readInto(array []byte, mask []byte, dst interface{}) error {
+	switch p := dst.(type) {
+	case *uint:
+		*p = Read[uint](array, mask)
+	case *uint8:
+		*p = Read[uint8](array, mask)
+	case *uint16:
+		*p = Read[uint16](array, mask)
+	case *uint32:
+		*p = Read[uint32](array, mask)
+	case *uint64:
+		*p = Read[uint64](array, mask)
+	case *int:
+		*p = Read[int](array, mask)
+	case *int8:
+		*p = Read[int8](array, mask)
+	case *int16:
+		*p = Read[int16](array, mask)
+	case *int32:
+		*p = Read[int32](array, mask)
+	case *int64:
+		*p = Read[int64](array, mask)
+	case *float32:
+		*p = Read[float32](array, mask)
+	case *float64:
+		*p = Read[float64](array, mask)
+	case *uintptr:
+		*p = Read[uintptr](array, mask)
+	case *bool:
+		*p = isSet(array, mask)
+	default:
+		return ErrInterfaceTypeNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package bitbytepack
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultReadInto(t *testing.T) {
+	array := []byte{0x12, 0x34, 0x56, 0x78}
+	masks := [][]byte{
+		{0xF0, 0xF0, 0x00, 0x00},
+		{0x0F, 0x0F, 0x00, 0x00},
+		{0xFF, 0x00, 0xFF, 0x00},
+		{0x00, 0xFF, 0x00, 0x0F}}
+	want := []uint64{0x13, 0x24, 0x1256, 0x348}
+
+	dst := make([]uint64, len(masks))
+	if e := MultReadInto(array, dst, masks...); e != nil || !reflect.DeepEqual(dst, want) {
+		t.Errorf("MultReadInto(%x, dst, %x) = %x, '%v', want %x", array, masks, dst, e, want)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { MultReadInto(array, dst, masks...) }); allocs != 0 {
+		t.Errorf("MultReadInto allocates %.0f times, want 0", allocs)
+	}
+
+	if e := MultReadInto(array, dst[:3], masks...); e != ErrDestinationTooShort {
+		t.Errorf("MultReadInto(%x, dst[:3], %x) didn't throw '%s', but '%s'", array, masks, ErrDestinationTooShort, e)
+	}
+	if e := MultReadInto(array[:3], dst, masks...); e != ErrArrayShorterThanMask {
+		t.Errorf("MultReadInto(%x, dst, %x) didn't throw '%s', but '%s'", array[:3], masks, ErrArrayShorterThanMask, e)
+	}
+}
+
+func TestMultReadFromArrayInto(t *testing.T) {
+	array := []byte{0x12, 0x34, 0x56, 0xF8}
+	masks := [][]byte{
+		{0xF0, 0xF0, 0x00, 0x00},
+		{0x0F, 0x0F, 0x00, 0x00},
+		{0xFF, 0x00, 0xFF, 0x00},
+		{0x00, 0x00, 0x00, 0xF0}}
+
+	var a uint8
+	var b int
+	var c uint16
+	var d int8
+	dst := []interface{}{&a, &b, &c, &d}
+
+	if e := MultReadFromArrayInto(array, dst, masks...); e != nil || a != 0x13 || b != 0x24 || c != 0x1256 || d != -1 {
+		t.Errorf("MultReadFromArrayInto(%x, dst, %x) = %x, %x, %x, %d, '%v'", array, masks, a, b, c, d, e)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { MultReadFromArrayInto(array, dst, masks...) }); allocs != 0 {
+		t.Errorf("MultReadFromArrayInto allocates %.0f times, want 0", allocs)
+	}
+
+	var s string
+	dst[3] = &s
+	if e := MultReadFromArrayInto(array, dst, masks...); e != ErrInterfaceTypeNotSupported {
+		t.Errorf("MultReadFromArrayInto(%x, dst, %x) didn't throw '%s', but '%s'", array, masks, ErrInterfaceTypeNotSupported, e)
+	}
+	if e := MultReadFromArrayInto(array, dst[:3], masks...); e != ErrDestinationTooShort {
+		t.Errorf("MultReadFromArrayInto(%x, dst[:3], %x) didn't throw '%s', but '%s'", array, masks, ErrDestinationTooShort, e)
+	}
+}
@@ -0,0 +1,107 @@
+package bitbytepack
+
+import "math"
+
+// Struct type to contain a mask array and a physical value, embedded as
+// round((Value - Bias) / Scale)
+type MaskValuePairScaled struct {
+	Mask   []byte  // mask array
+	Value  float64 // physical value to be embedded
+	Scale  float64 // physical value of one step of the raw value
+	Bias   float64 // physical value of a raw value of 0
+	Signed bool    // embed the raw value as two's complement
+	Offset int     // index in the array of the first byte of the mask
+}
+
+// Write the value to the array using the mask
+func (m MaskValuePairScaled) write(array []byte, opts writeOptions) ([]byte, error) {
+	raw, err := toRaw(m.Mask, m.Value, m.Scale, m.Bias, m.Signed, opts.overflow)
+	if err != nil {
+		return array, err
+	}
+	if m.Signed {
+		return MaskValue[int64]{m.Mask, int64(raw), m.Offset}.write(array, opts)
+	}
+	return MaskValue[uint64]{m.Mask, uint64(raw), m.Offset}.write(array, opts)
+}
+
+// Offset and mask of the MaskValuePairScaled
+func (m MaskValuePairScaled) placement() (int, []byte) {
+	return m.Offset, m.Mask
+}
+
+// Value of the MaskValuePairScaled
+func (m MaskValuePairScaled) value() interface{} {
+	return m.Value
+}
+
+// Read a scaled value of an array, as raw * scale + bias, where raw is the
+// masked value read as unsigned or as two's complement if signed is set
+func ReadScaled(array []byte, mask []byte, scale float64, bias float64, signed bool) float64 {
+	if signed {
+		return float64(ReadFromArray64S(array, mask))*scale + bias
+	}
+	return float64(ReadFromArray64(array, mask))*scale + bias
+}
+
+// Write a scaled value to an array, as round((value - bias) / scale). Returns
+// ErrNotEnoughBitsToEmbedValue if the rounded value doesn't fit in the mask.
+func WriteScaled(array []byte, mask []byte, value float64, scale float64, bias float64, signed bool) ([]byte, error) {
+	return MaskValuePairScaled{mask, value, scale, bias, signed, 0}.write(array, writeOptions{})
+}
+
+// Convert a physical value to the raw value to embed, checking that it fits in
+// the mask or handling it as given by overflow
+func toRaw(mask []byte, value float64, scale float64, bias float64, signed bool, overflow Overflow) (float64, error) {
+	raw := math.Round((value - bias) / scale)
+
+	width := CountOnes(mask)
+	if width > 64 {
+		width = 64
+	}
+
+	lo, hi := 0.0, math.Ldexp(1, width)
+	if signed {
+		lo, hi = -math.Ldexp(1, width-1), math.Ldexp(1, width-1)
+	}
+
+	// Also false for NaN
+	if !(raw >= lo && raw < hi) {
+		switch {
+		case math.IsNaN(raw):
+		case overflow == OverflowSaturate:
+			// Largest raw value below hi, as hi - 1 rounds to hi for 64 bits
+			return math.Max(lo, math.Min(raw, math.Floor(math.Nextafter(hi, lo)))), nil
+		case overflow == OverflowTruncate && !math.IsInf(raw, 0):
+			span := math.Ldexp(1, width)
+			if raw = math.Mod(raw, span); raw < 0 {
+				raw += span
+			}
+			if raw >= hi {
+				raw -= span
+			}
+			return raw, nil
+		}
+		return 0, ErrNotEnoughBitsToEmbedValue
+	}
+	return raw, nil
+}
+
+// Read a signed Q-format fixed point value of an array, such as Q8.8. The
+// integer bits include the sign bit, and the mask must have exactly
+// intBits + fracBits bits.
+func ReadQ(array []byte, mask []byte, intBits int, fracBits int) (float64, error) {
+	if CountOnes(mask) != intBits+fracBits {
+		return 0, ErrInvalidQFormat
+	}
+	return ReadScaled(array, mask, math.Ldexp(1, -fracBits), 0, true), nil
+}
+
+// Write a signed Q-format fixed point value to an array, rounded to the
+// nearest step, see ReadQ
+func WriteQ(array []byte, mask []byte, value float64, intBits int, fracBits int) ([]byte, error) {
+	if CountOnes(mask) != intBits+fracBits {
+		return array, ErrInvalidQFormat
+	}
+	return WriteScaled(array, mask, value, math.Ldexp(1, -fracBits), 0, true)
+}
@@ -0,0 +1,131 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestReadScaled(t *testing.T) {
+	array := []byte{0xFB, 0x2E}
+	mask := []byte{0xFF, 0xFF}
+	if got, want := ReadScaled(array, mask, 0.01, 0, true), -12.34; math.Abs(got-want) > 1e-9 {
+		t.Errorf("ReadScaled(%x, %x, 0.01, 0, true) = %f, want %f", array, mask, got, want)
+	}
+
+	array = []byte{0x82}
+	mask = []byte{0xFF}
+	if got, want := ReadScaled(array, mask, 0.5, -40, false), 25.0; got != want {
+		t.Errorf("ReadScaled(%x, %x, 0.5, -40, false) = %f, want %f", array, mask, got, want)
+	}
+}
+
+func TestWriteScaled(t *testing.T) {
+	mask := []byte{0xFF, 0xFF}
+	want := []byte{0xFB, 0x2E}
+	if got, e := WriteScaled(make([]byte, 2), mask, -12.34, 0.01, 0, true); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteScaled(-12.34, 0.01, 0, true) = %x, '%v', want %x", got, e, want)
+	}
+
+	// Rounded to the nearest step
+	want = []byte{0x04, 0xD3}
+	if got, e := WriteScaled(make([]byte, 2), mask, 12.345, 0.01, 0, false); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteScaled(12.345, 0.01, 0, false) = %x, '%v', want %x", got, e, want)
+	}
+
+	mask = []byte{0xFF}
+	want = []byte{0x82}
+	if got, e := WriteScaled(make([]byte, 1), mask, 25, 0.5, -40, false); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("WriteScaled(25, 0.5, -40, false) = %x, '%v', want %x", got, e, want)
+	}
+
+	for _, tt := range []struct {
+		value  float64
+		signed bool
+	}{
+		{88, false},
+		{-40.5, false},
+		{math.NaN(), false},
+		{math.Inf(1), true},
+		{24, true},
+		{-104.5, true},
+	} {
+		if _, e := WriteScaled(make([]byte, 1), mask, tt.value, 0.5, -40, tt.signed); e != ErrNotEnoughBitsToEmbedValue {
+			t.Errorf("WriteScaled(%f, 0.5, -40, %t) didn't throw '%s', but '%v'",
+				tt.value, tt.signed, ErrNotEnoughBitsToEmbedValue, e)
+		}
+	}
+
+	array := make([]byte, 3)
+	maskValuePairs := []interface{}{
+		MaskValuePairScaled{Mask: []byte{0xFF, 0xFF}, Value: -12.34, Scale: 0.01, Signed: true},
+		MaskValuePairScaled{Mask: []byte{0xFF}, Value: 25, Scale: 0.5, Bias: -40, Offset: 2},
+	}
+	want = []byte{0xFB, 0x2E, 0x82}
+	if got, e := MultWriteToArray(array, maskValuePairs...); e != nil || !bytes.Equal(got, want) {
+		t.Errorf("MultWriteToArray(%x, %v) = %x, '%v', want %x", array, maskValuePairs, got, e, want)
+	}
+}
+
+func TestQFormat(t *testing.T) {
+	mask := []byte{0xFF, 0xFF}
+	tests := []struct {
+		array []byte
+		value float64
+	}{
+		{[]byte{0x01, 0x80}, 1.5},
+		{[]byte{0xFE, 0x80}, -1.5},
+		{[]byte{0x7F, 0xFF}, 127.99609375},
+	}
+
+	for _, tt := range tests {
+		if got, e := ReadQ(tt.array, mask, 8, 8); e != nil || got != tt.value {
+			t.Errorf("ReadQ(%x, %x, 8, 8) = %f, '%v', want %f", tt.array, mask, got, e, tt.value)
+		}
+		if got, e := WriteQ(make([]byte, 2), mask, tt.value, 8, 8); e != nil || !bytes.Equal(got, tt.array) {
+			t.Errorf("WriteQ(%f, 8, 8) = %x, '%v', want %x", tt.value, got, e, tt.array)
+		}
+	}
+
+	if _, e := WriteQ(make([]byte, 2), mask, 128, 8, 8); e != ErrNotEnoughBitsToEmbedValue {
+		t.Errorf("WriteQ(128, 8, 8) didn't throw '%s', but '%v'", ErrNotEnoughBitsToEmbedValue, e)
+	}
+	if _, e := ReadQ([]byte{0x01, 0x80}, mask, 4, 8); e != ErrInvalidQFormat {
+		t.Errorf("ReadQ(0180, %x, 4, 8) didn't throw '%s', but '%v'", mask, ErrInvalidQFormat, e)
+	}
+	if _, e := WriteQ(make([]byte, 2), mask, 1, 4, 8); e != ErrInvalidQFormat {
+		t.Errorf("WriteQ(1, 4, 8) didn't throw '%s', but '%v'", ErrInvalidQFormat, e)
+	}
+}
+
+func TestScaledOverflow(t *testing.T) {
+	tests := []struct {
+		mask     []byte
+		value    float64
+		signed   bool
+		overflow Overflow
+		want     []byte
+	}{
+		{[]byte{0xFF}, 3.0, false, OverflowSaturate, []byte{0xFF}},
+		{[]byte{0xFF}, -1.0, false, OverflowSaturate, []byte{0x00}},
+		{[]byte{0xFF}, 1.5, true, OverflowSaturate, []byte{0x7F}},
+		{[]byte{0xFF}, -1.5, true, OverflowSaturate, []byte{0x80}},
+		{[]byte{0xFF}, 3.0, false, OverflowTruncate, []byte{0x2C}},
+		{[]byte{0xFF}, -0.01, false, OverflowTruncate, []byte{0xFF}},
+		{[]byte{0xFF}, 1.5, true, OverflowTruncate, []byte{0x96}},
+		{bytes.Repeat([]byte{0xFF}, 8), 1e30, false, OverflowSaturate, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xF8, 0x00}},
+	}
+
+	for _, tt := range tests {
+		m := MaskValuePairScaled{tt.mask, tt.value, 0.01, 0, tt.signed, 0}
+		if got, e := MultWriteToArrayOverflow(make([]byte, len(tt.mask)), tt.overflow, m); e != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("MultWriteToArrayOverflow(%d, %+v) = %x, '%v', want %x", tt.overflow, m, got, e, tt.want)
+		}
+	}
+
+	m := MaskValuePairScaled{[]byte{0xFF}, math.NaN(), 1, 0, false, 0}
+	if _, e := MultWriteToArrayOverflow([]byte{0x00}, OverflowSaturate, m); !errors.Is(e, ErrNotEnoughBitsToEmbedValue) {
+		t.Errorf("MultWriteToArrayOverflow(%+v) didn't throw '%s', but '%v'", m, ErrNotEnoughBitsToEmbedValue, e)
+	}
+}
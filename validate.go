@@ -0,0 +1,132 @@
+package bitbytepack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error of a single mask, found when validating masks
+type MaskError struct {
+	Index int   // index of the mask
+	Other int   // index of the mask overlapping with it, or -1
+	Err   error // ErrMasksOverlap, ErrMaskExceedsFrame, ErrEmptyMask or ErrInterfaceTypeNotSupported
+}
+
+func (e *MaskError) Error() string {
+	if e.Err == ErrMasksOverlap {
+		return fmt.Sprintf("masks %d and %d overlap", e.Other, e.Index)
+	}
+	return fmt.Sprintf("mask %d: %s", e.Index, e.Err)
+}
+
+func (e *MaskError) Unwrap() error {
+	return e.Err
+}
+
+// Error of a single field of a multi-field write
+type FieldError struct {
+	Index int         // index of the field
+	Name  string      // name of the field, if it has one
+	Mask  []byte      // mask of the field
+	Value interface{} // value that couldn't be written
+	Err   error       // reason the value couldn't be written
+}
+
+func (e *FieldError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("field %q: %s", e.Name, e.Err)
+	}
+	return fmt.Sprintf("field %d: %s", e.Index, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate that the masks of a frame of frameLen bytes fit in the frame,
+// are not empty and don't overlap. All the problems found are returned as
+// *MaskError, joined into one error.
+func ValidateMasks(frameLen int, masks ...[]byte) error {
+	return validateMasks(frameLen, make([]int, len(masks)), masks)
+}
+
+// Validate masks starting at the given offsets in the frame
+func validateMasks(frameLen int, offsets []int, masks [][]byte) error {
+	var errs []error
+
+	for i, m := range masks {
+		if offsets[i] < 0 || offsets[i]+len(m) > frameLen {
+			errs = append(errs, &MaskError{i, -1, ErrMaskExceedsFrame})
+		}
+		if CountOnes(m) == 0 {
+			errs = append(errs, &MaskError{i, -1, ErrEmptyMask})
+		}
+
+		for j := 0; j < i; j++ {
+			if overlap(offsets[j], masks[j], offsets[i], m) {
+				errs = append(errs, &MaskError{i, j, ErrMasksOverlap})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check if two masks starting at the given offsets share any bits
+func overlap(offsetA int, a []byte, offsetB int, b []byte) bool {
+	for i, m := range a {
+		j := offsetA + i - offsetB
+		if j >= 0 && j < len(b) && m&b[j] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Like MultReadFromArray, but validates the masks against the array first,
+// see ValidateMasks
+func MultReadFromArrayChecked(array []byte, mask ...MaskTypePair) ([]interface{}, error) {
+	return multReadFromArrayChecked(array, noLimit, mask)
+}
+
+// Like MultReadFromArrayChecked, but returns ErrTooManyValues if there are
+// more than limit masks, for decoding layouts received from untrusted sources
+func MultReadFromArrayCheckedLimit(array []byte, limit int, mask ...MaskTypePair) ([]interface{}, error) {
+	return multReadFromArrayChecked(array, limit, mask)
+}
+
+func multReadFromArrayChecked(array []byte, limit int, mask []MaskTypePair) ([]interface{}, error) {
+	if err := checkCount(len(mask), limit); err != nil {
+		return nil, err
+	}
+	offsets := make([]int, len(mask))
+	masks := make([][]byte, len(mask))
+	for i, m := range mask {
+		offsets[i], masks[i] = m.Offset, m.Mask
+	}
+
+	if err := validateMasks(len(array), offsets, masks); err != nil {
+		return nil, err
+	}
+	return multReadFromArrayE(array, noLimit, mask)
+}
+
+// Like MultWriteToArray, but validates the masks against the array before
+// writing anything, see ValidateMasks. Values that are not MaskValue pairs
+// give a *MaskError wrapping ErrInterfaceTypeNotSupported.
+func MultWriteToArrayChecked(array []byte, mvp ...interface{}) ([]byte, error) {
+	offsets := make([]int, len(mvp))
+	masks := make([][]byte, len(mvp))
+	for i, m := range mvp {
+		w, ok := m.(maskValueWriter)
+		if !ok {
+			return array, &MaskError{i, -1, ErrInterfaceTypeNotSupported}
+		}
+		offsets[i], masks[i] = w.placement()
+	}
+
+	if err := validateMasks(len(array), offsets, masks); err != nil {
+		return array, err
+	}
+	return MultWriteToArray(array, mvp...)
+}
@@ -0,0 +1,86 @@
+package bitbytepack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateMasks(t *testing.T) {
+	if e := ValidateMasks(2, []byte{0xF0, 0x0F}, []byte{0x0F, 0xF0}); e != nil {
+		t.Errorf("ValidateMasks threw '%s'", e)
+	}
+
+	e := ValidateMasks(2,
+		[]byte{0xF0, 0x0F},
+		[]byte{0x00, 0x00},
+		[]byte{0x10, 0x00},
+		[]byte{0x00, 0x00, 0x01},
+		[]byte{0x00, 0x01})
+	want := []MaskError{
+		{1, -1, ErrEmptyMask},
+		{2, 0, ErrMasksOverlap},
+		{3, -1, ErrMaskExceedsFrame},
+		{4, 0, ErrMasksOverlap},
+	}
+
+	var got []MaskError
+	for _, err := range e.(interface{ Unwrap() []error }).Unwrap() {
+		var me *MaskError
+		if !errors.As(err, &me) {
+			t.Fatalf("ValidateMasks returned '%s', which is not a *MaskError", err)
+		}
+		got = append(got, *me)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateMasks = %v, want %v", got, want)
+	}
+
+	for _, sentinel := range []error{ErrEmptyMask, ErrMasksOverlap, ErrMaskExceedsFrame} {
+		if !errors.Is(e, sentinel) {
+			t.Errorf("ValidateMasks error '%s' is not '%s'", e, sentinel)
+		}
+	}
+}
+
+func TestMultChecked(t *testing.T) {
+	array := []byte{0x12, 0x34}
+	masks := []MaskTypePair{
+		{[]byte{0xF0}, reflect.Uint8, 0, 0},
+		{[]byte{0xFF}, reflect.Uint8, 1, 0}}
+	want := []interface{}{uint8(0x1), uint8(0x34)}
+	if got, e := MultReadFromArrayChecked(array, masks...); e != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("MultReadFromArrayChecked(%x, %x) = %x, '%v', want %x", array, masks, got, e, want)
+	}
+
+	masks = append(masks, MaskTypePair{[]byte{0x01}, reflect.Uint8, 1, 0})
+	var me *MaskError
+	if _, e := MultReadFromArrayChecked(array, masks...); !errors.As(e, &me) || me.Index != 2 || me.Other != 1 {
+		t.Errorf("MultReadFromArrayChecked(%x, %x) didn't report masks 1 and 2 overlapping, but '%v'", array, masks, e)
+	}
+
+	array = []byte{0x00, 0x00}
+	maskValuePairs := []interface{}{
+		MaskValuePair8{[]byte{0xFF}, 0x12, 0},
+		MaskValuePair8{[]byte{0x0F}, 0x3, 1},
+	}
+	wantArray := []byte{0x12, 0x03}
+	if got, e := MultWriteToArrayChecked(array, maskValuePairs...); e != nil || !bytes.Equal(got, wantArray) {
+		t.Errorf("MultWriteToArrayChecked(%x, %x) = %x, '%v', want %x", array, maskValuePairs, got, e, wantArray)
+	}
+
+	// Nothing is written when the masks are invalid
+	array = []byte{0x00, 0x00}
+	maskValuePairs = append(maskValuePairs, MaskValuePair8{[]byte{0x01}, 0x1, 0})
+	wantArray = []byte{0x00, 0x00}
+	if got, e := MultWriteToArrayChecked(array, maskValuePairs...); !errors.Is(e, ErrMasksOverlap) || !bytes.Equal(got, wantArray) {
+		t.Errorf("MultWriteToArrayChecked(%x, %x) = %x, '%v', want %x, '%s'",
+			array, maskValuePairs, got, e, wantArray, ErrMasksOverlap)
+	}
+	if _, e := MultWriteToArrayChecked(array, maskValuePairs[0], "not a pair"); !errors.As(e, &me) ||
+		me.Index != 1 || !errors.Is(e, ErrInterfaceTypeNotSupported) {
+		t.Errorf("MultWriteToArrayChecked(%x, pair, string) didn't report mask 1 as '%s', but '%v'",
+			array, ErrInterfaceTypeNotSupported, e)
+	}
+}